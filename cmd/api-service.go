@@ -5,6 +5,7 @@ import (
 	"log"
 	"smart-scene-app-api/common"
 	"smart-scene-app-api/server"
+	"smart-scene-app-api/services"
 	logger2 "smart-scene-app-api/services/logger"
 	postgres3 "smart-scene-app-api/services/postgres"
 	"smart-scene-app-api/services/rest_api_service"
@@ -37,10 +38,23 @@ var restApiServiceCmd = &cobra.Command{
 				logger.Error().Println("NewMainPostgres", err)
 				return
 			}
+			err, storage := services.NewMainStorage(common.PREFIX_MAIN_STORAGE)
+			if err != nil {
+				logger.Error().Println("NewMainStorage", err)
+				return
+			}
+
+			err, redis := services.NewMainRedis(common.PREFIX_MAIN_REDIS)
+			if err != nil {
+				logger.Error().Println("NewMainRedis", err)
+				return
+			}
 
 			svr.AddLogger(logger)
 			svr.InitContext(ctx)
 			svr.InitService(postgres)
+			svr.InitService(storage)
+			svr.InitService(redis)
 			svr.AddHandler(restHdl)
 			if err := svr.Run(); err != nil {
 				logger.Error().Printf("Server is stopped by %v", err.Error())
@@ -9,6 +9,7 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	rootCmd.AddCommand(restApiServiceCmd)
+	rootCmd.AddCommand(workerCmd)
 
 	InitFlags()
 	rootCmd.Execute()
@@ -16,5 +17,6 @@ func Execute() {
 
 func InitFlags() {
 	restApiServiceCmd.PersistentFlags().Bool("start", false, "Command to start service with default port 8080")
+	workerCmd.PersistentFlags().Bool("start", false, "Command to start the queue worker")
 
 }
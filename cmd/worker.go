@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/worker"
+	"smart-scene-app-api/server"
+	"smart-scene-app-api/services"
+	logger2 "smart-scene-app-api/services/logger"
+	postgres3 "smart-scene-app-api/services/postgres"
+
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Start a queue worker",
+	Long:  "Consume the video analysis queue until the process is stopped",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		common.FetchMasterErrData()
+
+		loggerPkg := logger2.NewLogger("Logger")
+		if err := loggerPkg.Run(); err != nil {
+			log.Panic(err)
+		}
+
+		logger := loggerPkg.Get()
+
+		start, _ := cmd.Flags().GetBool("start")
+
+		if start {
+			// The worker serves no HTTP traffic; the port only satisfies the server configuration.
+			svr := server.NewServer("SmartSceneWorker", 8081)
+			err, postgres := postgres3.NewMainPostgres(common.PREFIX_MAIN_POSTGRES)
+			if err != nil {
+				logger.Error().Println("NewMainPostgres", err)
+				return
+			}
+			err, storage := services.NewMainStorage(common.PREFIX_MAIN_STORAGE)
+			if err != nil {
+				logger.Error().Println("NewMainStorage", err)
+				return
+			}
+			err, redis := services.NewMainRedis(common.PREFIX_MAIN_REDIS)
+			if err != nil {
+				logger.Error().Println("NewMainRedis", err)
+				return
+			}
+
+			svr.AddLogger(logger)
+			svr.InitContext(ctx)
+			svr.InitService(postgres)
+			svr.InitService(storage)
+			svr.InitService(redis)
+
+			err, analysisWorker := worker.NewVideoAnalysisWorker(common.PREFIX_MAIN_WORKER, svr)
+			if err != nil {
+				logger.Error().Println("NewVideoAnalysisWorker", err)
+				return
+			}
+			worker.RegisterTrashPurge(analysisWorker, svr)
+			svr.InitService(analysisWorker)
+			if err := svr.Run(); err != nil {
+				logger.Error().Printf("Worker is stopped by %v", err.Error())
+			}
+		}
+	},
+}
@@ -6,15 +6,28 @@ const (
 	POSTGRES_TABLE_NAME_ROLES = "roles"
 
 	// Video tables
-	POSTGRES_TABLE_NAME_VIDEOS = "videos"
+	POSTGRES_TABLE_NAME_VIDEOS                 = "videos"
+	POSTGRES_TABLE_NAME_VIDEO_STATUS_HISTORIES = "video_status_histories"
+	POSTGRES_TABLE_NAME_VIDEO_TAGS             = "video_tags"
+	POSTGRES_TABLE_NAME_VIDEO_SHARES           = "video_shares"
+
+	// Upload tables
+	POSTGRES_TABLE_NAME_UPLOAD_SESSIONS = "upload_sessions"
+	POSTGRES_TABLE_NAME_UPLOAD_PARTS    = "upload_parts"
 
 	// Character tables
 	POSTGRES_TABLE_NAME_CHARACTERS            = "characters"
 	POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES = "character_appearances"
+	POSTGRES_TABLE_NAME_CHARACTER_MERGES      = "character_merges"
 
 	// Tag tables
 	POSTGRES_TABLE_NAME_TAGS                    = "tags"
 	POSTGRES_TABLE_NAME_TAG_POSITIONS           = "tag_positions"
 	POSTGRES_TABLE_NAME_TAG_CATEGORIES          = "tag_categories"
 	POSTGRES_TABLE_NAME_TAG_POSITION_CATEGORIES = "tag_position_categories"
+
+	// Collection tables
+	POSTGRES_TABLE_NAME_COLLECTIONS       = "collections"
+	POSTGRES_TABLE_NAME_COLLECTION_ITEMS  = "collection_items"
+	POSTGRES_TABLE_NAME_COLLECTION_SHARES = "collection_shares"
 )
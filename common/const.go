@@ -14,10 +14,18 @@ const (
 
 const (
 	PREFIX_MAIN_POSTGRES       = "MAIN_POSTGRES"
+	PREFIX_MAIN_STORAGE        = "MAIN_STORAGE"
+	PREFIX_MAIN_REDIS          = "MAIN_REDIS"
+	PREFIX_MAIN_WORKER         = "MAIN_WORKER"
 	PREFIX_YOUPASS_DO_STORAGE  = "YOUPASS_DO_STORAGE"
 	PREFIX_CRONJOB_AUTO_CANCEL = "CRONJOB_AUTO_CANCEL"
 )
 
+const (
+	QUEUE_VIDEO_ANALYSIS    = "video_analysis"
+	JOB_TYPE_VIDEO_ANALYSIS = "video.analyze"
+)
+
 const ( //must NOT edit this
 	ENV_GIN_DEBUG  = "GIN_DEBUG"
 	ENV_RABBIT_URI = "RABBIT"
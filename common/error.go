@@ -3,9 +3,56 @@ package common
 import "errors"
 
 var (
-	ErrUserNotFound     = errors.New("user not found")
-	ErrInvalidPassword  = errors.New("invalid password")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrVideoNotFound    = errors.New("video not found")
-	ErrInvalidUUID      = errors.New("invalid UUID format")
-) 
\ No newline at end of file
+	ErrUserNotFound              = errors.New("user not found")
+	ErrInvalidPassword           = errors.New("invalid password")
+	ErrUserAlreadyExists         = errors.New("user already exists")
+	ErrVideoNotFound             = errors.New("video not found")
+	ErrInvalidUUID               = errors.New("invalid UUID format")
+	ErrInvalidVideoStatus        = errors.New("invalid video status")
+	ErrInvalidStatusTransition   = errors.New("invalid video status transition")
+	ErrVideoStatusNotUpdatable   = errors.New("video status must be changed through the transitions endpoint")
+	ErrVideoNotInTrash           = errors.New("video is not in the trash")
+	ErrUploadNotFound            = errors.New("upload session not found")
+	ErrUploadNotActive           = errors.New("upload session is not active")
+	ErrInvalidUploadPart         = errors.New("invalid upload part")
+	ErrUploadChecksumMismatch    = errors.New("upload part checksum mismatch")
+	ErrUploadIncomplete          = errors.New("upload is missing parts")
+	ErrInvalidAppearance         = errors.New("invalid character appearance")
+	ErrCharacterInactive         = errors.New("character is not active")
+	ErrAppearanceNotFound        = errors.New("character appearance not found")
+	ErrWebhookUnknownIntegration = errors.New("unknown webhook integration")
+	ErrWebhookInvalidSignature   = errors.New("invalid webhook signature")
+	ErrWebhookTimestampExpired   = errors.New("webhook timestamp outside tolerance")
+	ErrWebhookReplay             = errors.New("webhook nonce already used")
+	ErrWebhookEventInProgress    = errors.New("webhook event is already being processed")
+	ErrInvalidWebhookEvent       = errors.New("invalid webhook event")
+	ErrTagNotFound               = errors.New("tag not found")
+	ErrTagInactive               = errors.New("tag is not active")
+	ErrTagCategorySingle         = errors.New("category allows only one tag per video")
+	ErrVideoTagNotFound          = errors.New("tag is not attached to the video")
+	ErrInvalidTagRange           = errors.New("invalid tag range filter")
+	ErrInvalidSceneFilter        = errors.New("max_duration must not be less than min_duration")
+	ErrInvalidSceneExpression    = errors.New("invalid scene expression")
+	ErrInvalidCoOccurrence       = errors.New("invalid co-occurrence filter")
+	ErrInvalidCursor             = errors.New("invalid pagination cursor")
+	ErrInvalidSort               = errors.New("invalid sort")
+	ErrVideoForbidden            = errors.New("not allowed to perform this action on the video")
+	ErrInvalidVisibility         = errors.New("invalid visibility")
+	ErrInvalidVideoShare         = errors.New("a share needs exactly one of user_id or role_id")
+	ErrVideoShareNotFound        = errors.New("video share not found")
+	ErrCollectionNotFound        = errors.New("collection not found")
+	ErrCollectionForbidden       = errors.New("not allowed to perform this action on the collection")
+	ErrCollectionItemNotFound    = errors.New("collection item not found")
+	ErrInvalidCollectionItem     = errors.New("invalid collection item")
+	ErrInvalidCollectionOrder    = errors.New("the order must list every item of the collection once")
+	ErrCollectionShareNotFound   = errors.New("collection share not found")
+	ErrInvalidPatch              = errors.New("invalid merge patch")
+	ErrInvalidIfMatch            = errors.New("invalid If-Match header")
+	ErrVersionConflict           = errors.New("the resource has been modified since it was read")
+	ErrInvalidCharacter          = errors.New("invalid character")
+	ErrCharacterInUse            = errors.New("character still has appearances or video tags; delete with force to remove them")
+	ErrInvalidCharacterMerge     = errors.New("a merge needs existing source characters other than the target")
+	ErrCharacterMergeNotFound    = errors.New("character merge not found")
+	ErrCharacterMergeUndone      = errors.New("character merge has already been undone")
+	ErrCharacterForbidden        = errors.New("not allowed to perform this action on the character")
+)
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag formats the version of a resource as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatchVersion returns the version a request is conditional on, read from
+// its If-Match header. It returns 0, meaning unconditional, when the header is
+// absent or "*".
+func IfMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrInvalidIfMatch
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidIfMatch
+	}
+	return version, nil
+}
+
+// CheckVersion returns ErrVersionConflict when ifMatch is set and differs
+// from the current version of a resource.
+func CheckVersion(current, ifMatch int) error {
+	if ifMatch != 0 && ifMatch != current {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type UserJWTProfile struct {
@@ -62,6 +63,37 @@ func ProfileFromJwt(c *gin.Context) (bool, *UserJWTProfile) {
 
 }
 
+// UserIDFromJwt returns the ID of the authenticated user set by UserAuthentication.
+func UserIDFromJwt(c *gin.Context) (uuid.UUID, error) {
+	ok, profile := ProfileFromJwt(c)
+	if !ok || profile == nil {
+		return uuid.Nil, ErrCodeNotAuthorized
+	}
+	return uuid.Parse(profile.Id)
+}
+
+// Viewer is the authenticated caller as seen by access checks.
+type Viewer struct {
+	UserID uuid.UUID
+	RoleID uuid.UUID
+	Admin  bool
+}
+
+// ViewerFromJwt returns the caller set by UserAuthentication.
+func ViewerFromJwt(c *gin.Context) (Viewer, error) {
+	ok, profile := ProfileFromJwt(c)
+	if !ok || profile == nil {
+		return Viewer{}, ErrCodeNotAuthorized
+	}
+	userID, err := uuid.Parse(profile.Id)
+	if err != nil {
+		return Viewer{}, err
+	}
+	// Tokens issued before roles were added have no role.
+	roleID, _ := uuid.Parse(profile.Role)
+	return Viewer{UserID: userID, RoleID: roleID, Admin: profile.AdminAccess}, nil
+}
+
 func GenerateToken(profile *UserJWTProfile) (string, error) {
 	secretKey := []byte(config.Config.JwtSecret)
 
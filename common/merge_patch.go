@@ -0,0 +1,36 @@
+package common
+
+// MergePatch applies an RFC 7396 JSON merge patch to target and returns the
+// result; target is not modified. Objects are merged member by member and
+// recursively, a null member removes the member from the target, and any
+// other patch value replaces the target.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := asObject(patch)
+	if !ok {
+		return patch
+	}
+
+	targetObject, _ := asObject(target)
+	result := make(map[string]interface{}, len(targetObject)+len(patchObject))
+	for key, value := range targetObject {
+		result[key] = value
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = MergePatch(result[key], value)
+	}
+	return result
+}
+
+func asObject(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case JSON:
+		return v, true
+	}
+	return nil, false
+}
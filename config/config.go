@@ -44,6 +44,33 @@ type Schema struct {
 		ImgkitOutputEndpoint string `mapstructure:"imgkit_output_endpoint"`
 		StorageAcl           string `mapstructure:"storage_acl"`
 	} `mapstructure:"digital_ocean"`
+
+	Storage struct {
+		Driver        string `mapstructure:"driver"`
+		LocalRoot     string `mapstructure:"local_root"`
+		LocalBaseURL  string `mapstructure:"local_base_url"`
+		SigningSecret string `mapstructure:"signing_secret"`
+		SignedURLTTL  int64  `mapstructure:"signed_url_ttl"`
+	} `mapstructure:"storage"`
+	Webhook struct {
+		Secrets            string `mapstructure:"secrets"`
+		TimestampTolerance int64  `mapstructure:"timestamp_tolerance"`
+	} `mapstructure:"webhook"`
+
+	Trash struct {
+		RetentionDays int   `mapstructure:"retention_days"`
+		PurgeInterval int64 `mapstructure:"purge_interval"`
+	} `mapstructure:"trash"`
+
+	Worker struct {
+		Concurrency       int    `mapstructure:"concurrency"`
+		PollInterval      int64  `mapstructure:"poll_interval"`
+		VisibilityTimeout int64  `mapstructure:"visibility_timeout"`
+		MaxAttempts       int    `mapstructure:"max_attempts"`
+		RetryBackoff      int64  `mapstructure:"retry_backoff"`
+		DetectorURL       string `mapstructure:"detector_url"`
+	} `mapstructure:"worker"`
+
 	Http struct {
 		MaxIdleConnection     int `mapstructure:"max_idle_connection"`
 		IdleConnectionTimeout int `mapstructure:"idle_connection_timeout"`
@@ -99,6 +126,18 @@ func init() {
 	}
 }
 
+// WebhookSecret returns the signing secret of a webhook integration. Secrets
+// are configured as comma separated "integration:secret" pairs.
+func WebhookSecret(integration string) (string, bool) {
+	for _, pair := range strings.Split(Config.Webhook.Secrets, ",") {
+		name, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && name == integration && secret != "" {
+			return secret, true
+		}
+	}
+	return "", false
+}
+
 func GetPartKey(partID int) string {
 	return fmt.Sprintf("part_%v", partID)
 }
@@ -0,0 +1,18 @@
+-- Manual appearance review: confirmed and rejected appearances survive re-ingestion,
+-- rejected ones are kept only to suppress the detection and are ignored everywhere else
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS is_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE character_appearances SET is_confirmed = FALSE WHERE is_confirmed IS NULL;
+ALTER TABLE character_appearances ALTER COLUMN is_confirmed SET NOT NULL;
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS is_rejected BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS reviewed_by UUID;
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_character_appearances_reviewed ON character_appearances(video_id) WHERE is_confirmed OR is_rejected;
+
+CREATE OR REPLACE FUNCTION video_search_character_text(p_video_id UUID) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(string_agg(DISTINCT c.name, ' '), '')
+    FROM character_appearances ca
+    JOIN characters c ON c.id = ca.character_id
+    WHERE ca.video_id = p_video_id AND NOT ca.is_rejected
+$$;
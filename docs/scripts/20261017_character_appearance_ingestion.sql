@@ -0,0 +1,7 @@
+-- Bulk appearance ingestion (PUT /api/v1/videos/:id/appearances) stores frame ranges and detector metadata
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS start_frame INT NOT NULL DEFAULT 0;
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS end_frame INT NOT NULL DEFAULT 0;
+ALTER TABLE character_appearances ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE character_appearances ALTER COLUMN confidence TYPE DECIMAL(5,4);
+
+CREATE INDEX IF NOT EXISTS idx_character_appearances_video_id ON character_appearances(video_id, start_time);
@@ -0,0 +1,4 @@
+-- Character catalog: names are compared case-insensitively on create and rename
+CREATE INDEX IF NOT EXISTS idx_characters_lower_name ON characters (LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_character_appearances_character_id ON character_appearances(character_id);
+CREATE INDEX IF NOT EXISTS idx_video_tags_character_id ON video_tags(character_id) WHERE character_id IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Character merges: the snapshot keeps what a merge moved so that it can be undone
+CREATE TABLE character_merges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    created_by UUID NOT NULL REFERENCES users(id),
+    target_id UUID NOT NULL,
+    snapshot JSONB NOT NULL,
+    undone_at TIMESTAMPTZ,
+    undone_by UUID REFERENCES users(id)
+);
+
+CREATE INDEX idx_character_merges_target_id ON character_merges(target_id);
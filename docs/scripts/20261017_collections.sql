@@ -0,0 +1,51 @@
+-- Collections: user-owned, ordered lists of videos and scenes, with the same
+-- visibility levels and shares as videos
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_by UUID NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    visibility TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'shared', 'organization'))
+);
+
+CREATE INDEX idx_collections_created_by ON collections(created_by);
+
+-- video_id has no foreign key: items outlive purged videos and are shown as stale.
+-- The position constraint is deferred so that items can be reordered in one transaction.
+CREATE TABLE collection_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position >= 0),
+    video_id UUID NOT NULL,
+    start_time DECIMAL(10,3),
+    end_time DECIMAL(10,3),
+    note TEXT,
+    CONSTRAINT uq_collection_items_position UNIQUE (collection_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (start_time IS NULL OR (start_time >= 0 AND start_time < end_time))
+);
+
+CREATE INDEX idx_collection_items_video_id ON collection_items(video_id);
+
+CREATE TABLE collection_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    user_id UUID,
+    role_id UUID,
+    permission TEXT NOT NULL CHECK (permission IN ('viewer', 'editor')),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((user_id IS NULL) <> (role_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_collection_shares_collection_user ON collection_shares(collection_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_collection_shares_collection_role ON collection_shares(collection_id, role_id) WHERE role_id IS NOT NULL;
+CREATE INDEX idx_collection_shares_user_id ON collection_shares(user_id);
+CREATE INDEX idx_collection_shares_role_id ON collection_shares(role_id);
@@ -0,0 +1,3 @@
+-- Optimistic concurrency: the version is served as the ETag and checked against If-Match on PUT/PATCH/DELETE
+ALTER TABLE videos ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE characters ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+-- Range categories: tags carry the numeric bounds they stand for, matched by tag_ranges=category_code:min:max
+ALTER TABLE tags ADD COLUMN range_min NUMERIC;
+ALTER TABLE tags ADD COLUMN range_max NUMERIC;
+
+ALTER TABLE tags ADD CONSTRAINT chk_tags_range CHECK (range_min IS NULL OR range_max IS NULL OR range_min <= range_max);
+
+CREATE INDEX IF NOT EXISTS idx_video_tags_video_id ON video_tags(video_id, tag_id);
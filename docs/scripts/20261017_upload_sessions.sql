@@ -0,0 +1,28 @@
+-- Resumable chunked uploads
+CREATE TABLE upload_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ,
+    created_by UUID NOT NULL REFERENCES users(id),
+    status TEXT NOT NULL DEFAULT 'uploading' CHECK (status IN ('uploading', 'assembling', 'completed', 'aborted')),
+    title TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT,
+    total_size BIGINT NOT NULL,
+    chunk_size BIGINT NOT NULL,
+    total_chunks INTEGER NOT NULL,
+    duration INTEGER NOT NULL DEFAULT 0,
+    metadata JSONB,
+    video_id UUID REFERENCES videos(id),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE upload_parts (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    upload_id UUID NOT NULL REFERENCES upload_sessions(id),
+    part_number INTEGER NOT NULL,
+    size BIGINT NOT NULL,
+    checksum TEXT NOT NULL,
+    UNIQUE(upload_id, part_number)
+);
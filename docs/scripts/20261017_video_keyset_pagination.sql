@@ -0,0 +1,3 @@
+-- Keyset pagination: listings order by the sort columns then id, so the default sort needs a matching index
+CREATE INDEX IF NOT EXISTS idx_videos_created_at_id ON videos(created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_videos_updated_at_id ON videos(updated_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,121 @@
+-- Full-text search over videos: GET /api/v1/videos?q=...
+-- search_vector weights: A title, B tag and character names, C metadata strings.
+-- The 'simple' configuration is used because titles and names are not English-only.
+ALTER TABLE videos ADD COLUMN search_document TEXT;
+ALTER TABLE videos ADD COLUMN search_vector TSVECTOR;
+
+CREATE INDEX idx_videos_search_vector ON videos USING GIN (search_vector);
+
+CREATE OR REPLACE FUNCTION video_search_tag_text(p_video_id UUID) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(string_agg(DISTINCT t.name, ' '), '')
+    FROM video_tags vt
+    JOIN tags t ON t.id = vt.tag_id
+    WHERE vt.video_id = p_video_id AND t.is_active = true
+$$;
+
+CREATE OR REPLACE FUNCTION video_search_character_text(p_video_id UUID) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(string_agg(DISTINCT c.name, ' '), '')
+    FROM character_appearances ca
+    JOIN characters c ON c.id = ca.character_id
+    WHERE ca.video_id = p_video_id
+$$;
+
+CREATE OR REPLACE FUNCTION video_search_metadata_text(p_metadata JSONB) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT COALESCE(string_agg(v #>> '{}', ' '), '')
+    FROM jsonb_path_query(COALESCE(p_metadata, '{}'::jsonb), 'strict $.**') AS v
+    WHERE jsonb_typeof(v) = 'string'
+$$;
+
+-- Recomputes the search columns of a video row. Related tables refresh a video
+-- by setting search_vector to NULL, which fires this trigger again.
+CREATE OR REPLACE FUNCTION videos_search_refresh() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    tag_text       TEXT := video_search_tag_text(NEW.id);
+    character_text TEXT := video_search_character_text(NEW.id);
+    metadata_text  TEXT := video_search_metadata_text(NEW.metadata);
+BEGIN
+    NEW.search_document := concat_ws(' ', NEW.title, tag_text, character_text, metadata_text);
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', tag_text), 'B') ||
+        setweight(to_tsvector('simple', character_text), 'B') ||
+        setweight(to_tsvector('simple', metadata_text), 'C');
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_videos_search_refresh
+    BEFORE INSERT OR UPDATE OF title, metadata, search_vector ON videos
+    FOR EACH ROW EXECUTE FUNCTION videos_search_refresh();
+
+-- video_tags and character_appearances are written in bulk, so they refresh
+-- once per statement from their transition tables.
+CREATE OR REPLACE FUNCTION video_search_refresh_from_transition() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE videos SET search_vector = NULL WHERE id IN (SELECT DISTINCT video_id FROM new_rows);
+    END IF;
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE videos SET search_vector = NULL WHERE id IN (SELECT DISTINCT video_id FROM old_rows);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_video_tags_search_insert AFTER INSERT ON video_tags
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+CREATE TRIGGER trg_video_tags_search_update AFTER UPDATE ON video_tags
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+CREATE TRIGGER trg_video_tags_search_delete AFTER DELETE ON video_tags
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+
+CREATE TRIGGER trg_character_appearances_search_insert AFTER INSERT ON character_appearances
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+CREATE TRIGGER trg_character_appearances_search_update AFTER UPDATE ON character_appearances
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+CREATE TRIGGER trg_character_appearances_search_delete AFTER DELETE ON character_appearances
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION video_search_refresh_from_transition();
+
+-- Renaming or deactivating a tag and renaming a character are rare; they
+-- refresh every video that references them.
+CREATE OR REPLACE FUNCTION tags_search_refresh() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE videos SET search_vector = NULL
+    WHERE id IN (SELECT video_id FROM video_tags WHERE tag_id = NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_tags_search_refresh
+    AFTER UPDATE OF name, is_active ON tags
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name OR OLD.is_active IS DISTINCT FROM NEW.is_active)
+    EXECUTE FUNCTION tags_search_refresh();
+
+CREATE OR REPLACE FUNCTION characters_search_refresh() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE videos SET search_vector = NULL
+    WHERE id IN (SELECT DISTINCT video_id FROM character_appearances WHERE character_id = NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_characters_search_refresh
+    AFTER UPDATE OF name ON characters
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION characters_search_refresh();
+
+-- Backfill existing videos
+UPDATE videos SET search_vector = NULL;
@@ -0,0 +1,26 @@
+-- Video visibility and shares: private (owner only), shared (owner and shares),
+-- organization (every user views; shares grant editing)
+ALTER TABLE videos ADD COLUMN visibility TEXT NOT NULL DEFAULT 'private'
+    CHECK (visibility IN ('private', 'shared', 'organization'));
+
+-- Existing videos were visible to every user
+UPDATE videos SET visibility = 'organization';
+
+CREATE INDEX idx_videos_created_by ON videos(created_by);
+
+CREATE TABLE video_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    user_id UUID,
+    role_id UUID,
+    permission TEXT NOT NULL CHECK (permission IN ('viewer', 'editor')),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((user_id IS NULL) <> (role_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_video_shares_video_user ON video_shares(video_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_video_shares_video_role ON video_shares(video_id, role_id) WHERE role_id IS NOT NULL;
+CREATE INDEX idx_video_shares_user_id ON video_shares(user_id);
+CREATE INDEX idx_video_shares_role_id ON video_shares(role_id);
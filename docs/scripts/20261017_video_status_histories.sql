@@ -0,0 +1,15 @@
+-- Video status state machine: pending -> processing -> completed | failed, failed -> pending (retry)
+ALTER TABLE videos ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE TABLE video_status_histories (
+    id SERIAL PRIMARY KEY,
+    video_id UUID NOT NULL REFERENCES videos(id),
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL CHECK (to_status IN ('pending', 'processing', 'completed', 'failed')),
+    reason TEXT,
+    actor_type TEXT NOT NULL DEFAULT 'user' CHECK (actor_type IN ('user', 'system')),
+    actor_id UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_video_status_histories_video_id ON video_status_histories(video_id, created_at);
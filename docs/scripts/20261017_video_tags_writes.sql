@@ -0,0 +1,8 @@
+-- Video tag writes: one row per (video, tag, character); tags.usage_count counts video_tags rows
+CREATE UNIQUE INDEX idx_video_tags_unique
+    ON video_tags (video_id, tag_id, COALESCE(character_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+CREATE INDEX IF NOT EXISTS idx_video_tags_tag_id ON video_tags(tag_id);
+
+-- Resynchronise counters with the actual rows
+UPDATE tags t SET usage_count = COALESCE((SELECT COUNT(*) FROM video_tags vt WHERE vt.tag_id = t.id), 0);
@@ -0,0 +1,5 @@
+-- Soft delete: DELETE /api/v1/videos/:id moves videos to the trash, the worker purges them after trash.retention_days
+ALTER TABLE videos ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE videos ADD COLUMN deleted_by UUID REFERENCES users(id);
+
+CREATE INDEX idx_videos_deleted_at ON videos(deleted_at);
@@ -3,8 +3,12 @@ package handlers
 import (
 	authHandler "smart-scene-app-api/internal/handlers/auth"
 	characterHandler "smart-scene-app-api/internal/handlers/characters"
+	collectionHandler "smart-scene-app-api/internal/handlers/collections"
+	storageHandler "smart-scene-app-api/internal/handlers/storage"
 	tagHandler "smart-scene-app-api/internal/handlers/tags"
+	uploadHandler "smart-scene-app-api/internal/handlers/uploads"
 	videoHandler "smart-scene-app-api/internal/handlers/videos"
+	webhookHandler "smart-scene-app-api/internal/handlers/webhooks"
 	services "smart-scene-app-api/internal/services"
 	l "smart-scene-app-api/pkg/logger"
 	"smart-scene-app-api/server"
@@ -36,6 +40,18 @@ func (h *Handler) RegisterRouter(router *gin.Engine) {
 	character := characterHandler.NewHandler(h.sc)
 	character.RegisterRoutes(router)
 
+	upload := uploadHandler.NewHandler(h.sc)
+	upload.RegisterRoutes(router)
+
+	storage := storageHandler.NewHandler(h.sc)
+	storage.RegisterRoutes(router)
+
+	webhook := webhookHandler.NewHandler(h.sc)
+	webhook.RegisterRoutes(router)
+
+	collection := collectionHandler.NewHandler(h.sc)
+	collection.RegisterRoutes(router)
+
 	tagRoutes := router.Group("/api/v1")
 	tagHandler.RegisterTagRoutes(h.sc, tagRoutes)
 }
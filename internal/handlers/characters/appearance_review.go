@@ -0,0 +1,350 @@
+package character
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVideoAppearances godoc
+// @Summary      List the appearances of a video
+// @Description  List the character appearances of a video for review, rejected ones included, ordered by start time
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path   string  true   "Video ID"
+// @Param        character_id   query  string  false  "Character ID"
+// @Param        review_status  query  string  false  "pending, confirmed or rejected"
+// @Param        page           query  int     false  "Page number"
+// @Param        page_size      query  int     false  "Page size"
+// @Success      200  {object}  common.Response{data=character.AppearanceListResponse}  "List of appearances"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances [get]
+func (h *Handler) GetVideoAppearances(c *gin.Context) {
+	var queryParams character.VideoAppearanceFilterAndPagination
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.GetVideoAppearances(c.Param("id"), queryParams, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to retrieve appearances")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearances retrieved successfully",
+		Data:    result,
+	})
+}
+
+// CreateAppearance godoc
+// @Summary      Add an appearance
+// @Description  Add a character appearance by hand. Its frames are derived from the times using fps or the frame rate of the video. It is created confirmed, so re-ingesting detections keeps it.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string                             true   "Video ID"
+// @Param        If-Match  header  string                             false  "Video ETag the edit is conditional on"
+// @Param        request   body    character.CreateAppearanceRequest  true   "Appearance"
+// @Success      201  {object}  common.Response{data=character.CharacterAppearance}  "Appearance created successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances [post]
+func (h *Handler) CreateAppearance(c *gin.Context) {
+	var req character.CreateAppearanceRequest
+	viewer, ifMatch, ok := h.bindAppearanceEdit(c, &req)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Character.CreateAppearance(c.Param("id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to create appearance")
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Appearance created successfully",
+		Data:    result,
+	})
+}
+
+// UpdateAppearance godoc
+// @Summary      Edit an appearance
+// @Description  Trim, extend or reassign an appearance; omitted fields keep their value. Frames of the moved boundaries are derived from the new times. The appearance is marked confirmed.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path    string                             true   "Video ID"
+// @Param        appearance_id  path    string                             true   "Appearance ID"
+// @Param        If-Match       header  string                             false  "Video ETag the edit is conditional on"
+// @Param        request        body    character.UpdateAppearanceRequest  true   "Changes"
+// @Success      200  {object}  common.Response{data=character.CharacterAppearance}  "Appearance updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or appearance not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances/{appearance_id} [put]
+func (h *Handler) UpdateAppearance(c *gin.Context) {
+	var req character.UpdateAppearanceRequest
+	viewer, ifMatch, ok := h.bindAppearanceEdit(c, &req)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Character.UpdateAppearance(c.Param("id"), c.Param("appearance_id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to update appearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearance updated successfully",
+		Data:    result,
+	})
+}
+
+// SplitAppearance godoc
+// @Summary      Split an appearance
+// @Description  Cut an appearance in two at a time strictly inside it. Both halves are marked confirmed.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path    string                            true   "Video ID"
+// @Param        appearance_id  path    string                            true   "Appearance ID"
+// @Param        If-Match       header  string                            false  "Video ETag the edit is conditional on"
+// @Param        request        body    character.SplitAppearanceRequest  true   "Split time"
+// @Success      200  {object}  common.Response{data=character.AppearanceListResponse}  "Appearance split successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or appearance not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances/{appearance_id}/split [post]
+func (h *Handler) SplitAppearance(c *gin.Context) {
+	var req character.SplitAppearanceRequest
+	viewer, ifMatch, ok := h.bindAppearanceEdit(c, &req)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Character.SplitAppearance(c.Param("id"), c.Param("appearance_id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to split appearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearance split successfully",
+		Data:    result,
+	})
+}
+
+// MergeAppearances godoc
+// @Summary      Merge appearances
+// @Description  Join appearances of the same character into the earliest one, extended to span all of them and marked confirmed; the others are deleted
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string                             true   "Video ID"
+// @Param        If-Match  header  string                             false  "Video ETag the edit is conditional on"
+// @Param        request   body    character.MergeAppearancesRequest  true   "Appearances to merge"
+// @Success      200  {object}  common.Response{data=character.CharacterAppearance}  "Appearances merged successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or appearance not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances/merge [post]
+func (h *Handler) MergeAppearances(c *gin.Context) {
+	var req character.MergeAppearancesRequest
+	viewer, ifMatch, ok := h.bindAppearanceEdit(c, &req)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Character.MergeAppearances(c.Param("id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to merge appearances")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearances merged successfully",
+		Data:    result,
+	})
+}
+
+// DeleteAppearance godoc
+// @Summary      Delete an appearance
+// @Description  Delete an appearance. An automatic detection deleted this way comes back with the next ingestion; reject it to prevent that.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path    string  true   "Video ID"
+// @Param        appearance_id  path    string  true   "Appearance ID"
+// @Param        If-Match       header  string  false  "Video ETag the edit is conditional on"
+// @Success      200  {object}  common.Response  "Appearance deleted successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or appearance not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances/{appearance_id} [delete]
+func (h *Handler) DeleteAppearance(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Character.DeleteAppearance(c.Param("id"), c.Param("appearance_id"), ifMatch, viewer); err != nil {
+		h.writeAppearanceError(c, err, "Failed to delete appearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearance deleted successfully",
+	})
+}
+
+// ReviewAppearance godoc
+// @Summary      Review an appearance
+// @Description  Confirm or reject an appearance, or return it to pending. Confirmed and rejected appearances survive re-ingestion, which also drops detections overlapping them; rejected ones are hidden from scenes, counts and search.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path    string                             true   "Video ID"
+// @Param        appearance_id  path    string                             true   "Appearance ID"
+// @Param        If-Match       header  string                             false  "Video ETag the review is conditional on"
+// @Param        request        body    character.ReviewAppearanceRequest  true   "Review status"
+// @Success      200  {object}  common.Response{data=character.CharacterAppearance}  "Appearance reviewed successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or appearance not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/appearances/{appearance_id}/review [post]
+func (h *Handler) ReviewAppearance(c *gin.Context) {
+	var req character.ReviewAppearanceRequest
+	viewer, ifMatch, ok := h.bindAppearanceEdit(c, &req)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Character.ReviewAppearance(c.Param("id"), c.Param("appearance_id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeAppearanceError(c, err, "Failed to review appearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearance reviewed successfully",
+		Data:    result,
+	})
+}
+
+// bindAppearanceEdit binds the body, viewer and If-Match version of an
+// appearance edit, writing the error response when one is missing or invalid.
+func (h *Handler) bindAppearanceEdit(c *gin.Context, req interface{}) (common.Viewer, int, bool) {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid appearance data",
+			ErrorDetail: err.Error(),
+		})
+		return common.Viewer{}, 0, false
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return common.Viewer{}, 0, false
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return common.Viewer{}, 0, false
+	}
+	return viewer, ifMatch, true
+}
+
+func (h *Handler) writeAppearanceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidAppearance),
+		errors.Is(err, common.ErrCharacterNotFound),
+		errors.Is(err, common.ErrCharacterInactive):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoForbidden):
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoNotFound),
+		errors.Is(err, common.ErrAppearanceNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.writeCharacterError(c, err, message)
+	}
+}
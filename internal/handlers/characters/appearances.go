@@ -0,0 +1,113 @@
+package character
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplaceVideoAppearances godoc
+// @Summary      Ingest character appearances for a video
+// @Description  Atomically replace the unreviewed character appearances of a video with the detections produced by the analysis pipeline, recompute the video's character statistics and mark it completed. Confirmed and rejected appearances are kept, and detections overlapping one of the same character are skipped.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        video_id  path      string  true  "Video ID"
+// @Param        If-Match  header    string  false  "Video ETag the replacement is conditional on"
+// @Param        request   body      character.IngestAppearancesRequest  true  "Detected appearances"
+// @Success      200  {object}  common.Response{data=character.IngestAppearancesResponse}  "Appearances ingested successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      409  {object}  common.Response  "Video status does not allow completion"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{video_id}/appearances [put]
+func (h *Handler) ReplaceVideoAppearances(c *gin.Context) {
+	videoID := c.Param("id")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Video ID is required",
+			ErrorDetail: "The 'id' parameter is missing or empty",
+		})
+		return
+	}
+
+	var req character.IngestAppearancesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid appearance data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.ReplaceVideoAppearances(videoID, req, ifMatch, &viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrVersionConflict):
+			c.JSON(http.StatusPreconditionFailed, common.Response{
+				Message:     "Video has been modified",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrInvalidUUID),
+			errors.Is(err, common.ErrInvalidAppearance),
+			errors.Is(err, common.ErrCharacterNotFound),
+			errors.Is(err, common.ErrCharacterInactive):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid appearance data",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoForbidden):
+			c.JSON(http.StatusForbidden, common.Response{
+				Message:     "Not allowed to edit this video",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoNotFound):
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, common.Response{
+				Message:     "Video status does not allow completion",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to ingest character appearances: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to ingest character appearances",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Appearances ingested successfully",
+		Data:    result,
+	})
+}
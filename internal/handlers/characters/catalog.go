@@ -0,0 +1,361 @@
+package character
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCharacters godoc
+// @Summary      Get characters
+// @Description  Retrieve the character catalog with the number of videos visible to the caller each character appears in and its appearances in them
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name        query  string  false  "Name contains"
+// @Param        is_active   query  bool    false  "Active or inactive characters only"
+// @Param        created_by  query  string  false  "Creator user ID"
+// @Param        page        query  int     false  "Page number"
+// @Param        page_size   query  int     false  "Page size"
+// @Param        sort        query  string  false  "Sort fields: name, created_at, updated_at; defaults to name.asc"
+// @Success      200  {object}  common.Response{data=character.CharacterListResponse}  "List of characters"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters [get]
+func (h *Handler) GetCharacters(c *gin.Context) {
+	var queryParams character.CharacterFilterAndPagination
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	characters, err := h.service.Character.GetCharacters(queryParams, viewer)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		h.logger.Error("Failed to get characters: " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     "Failed to retrieve characters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Characters retrieved successfully",
+		Data:    characters,
+	})
+}
+
+// GetCharacter godoc
+// @Summary      Get a character
+// @Description  Retrieve a character with the number of videos visible to the caller it appears in and its appearances in them
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Character ID"
+// @Success      200  {object}  common.Response{data=character.CharacterResponse}  "Character details"
+// @Header       200  {string}  ETag  "Version of the character"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Character not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/{id} [get]
+func (h *Handler) GetCharacter(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.GetCharacter(c.Param("id"), viewer)
+	if err != nil {
+		h.writeCharacterError(c, err, "Failed to retrieve character")
+		return
+	}
+
+	c.Header("ETag", common.ETag(result.Version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Character retrieved successfully",
+		Data:    result,
+	})
+}
+
+// CreateCharacter godoc
+// @Summary      Create a character
+// @Description  Add a character to the catalog. Names are unique, case-insensitively.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      character.CreateCharacterRequest  true  "Character"
+// @Success      201  {object}  common.Response{data=character.CharacterResponse}  "Character created successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      409  {object}  common.Response  "Character name already exists"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters [post]
+func (h *Handler) CreateCharacter(c *gin.Context) {
+	var req character.CreateCharacterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid character data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.CreateCharacter(req, viewer)
+	if err != nil {
+		h.writeCharacterError(c, err, "Failed to create character")
+		return
+	}
+
+	c.Header("ETag", common.ETag(result.Version))
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Character created successfully",
+		Data:    result,
+	})
+}
+
+// UpdateCharacter godoc
+// @Summary      Update a character
+// @Description  Rename, describe or deactivate a character; only the fields present are changed. Only admins and the creator of the character can update it.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      string                            true   "Character ID"
+// @Param        If-Match  header    string                            false  "ETag the update is conditional on"
+// @Param        request   body      character.UpdateCharacterRequest  true   "Fields to update"
+// @Success      200  {object}  common.Response{data=character.CharacterResponse}  "Character updated successfully"
+// @Header       200  {string}  ETag  "New version of the character"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Character not found"
+// @Failure      409  {object}  common.Response  "Character name already exists"
+// @Failure      412  {object}  common.Response  "Character has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/{id} [put]
+func (h *Handler) UpdateCharacter(c *gin.Context) {
+	var req character.UpdateCharacterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid character data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.UpdateCharacter(c.Param("id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeCharacterError(c, err, "Failed to update character")
+		return
+	}
+
+	c.Header("ETag", common.ETag(result.Version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Character updated successfully",
+		Data:    result,
+	})
+}
+
+// DeleteCharacter godoc
+// @Summary      Delete a character
+// @Description  Delete a character that no appearance, rejected ones included, and no video tag refers to. Only admins and the creator of the character can delete it. With force=true, admins can delete a character together with its appearances and video tag attachments.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      string  true   "Character ID"
+// @Param        force     query     bool    false  "Also delete its appearances and video tags (admin only)"
+// @Param        If-Match  header    string  false  "ETag the deletion is conditional on"
+// @Success      204  {object}  common.Response  "Character deleted successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Character not found"
+// @Failure      409  {object}  common.Response  "Character still has appearances or video tags"
+// @Failure      412  {object}  common.Response  "Character has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/{id} [delete]
+func (h *Handler) DeleteCharacter(c *gin.Context) {
+	force := false
+	if raw := c.Query("force"); raw != "" {
+		var err error
+		if force, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Character.DeleteCharacter(c.Param("id"), force, ifMatch, viewer); err != nil {
+		h.writeCharacterError(c, err, "Failed to delete character")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, common.Response{
+		Message: "Character deleted successfully",
+	})
+}
+
+// GetCharacterVideos godoc
+// @Summary      Get the videos of a character
+// @Description  List the videos visible to the caller that a character appears in, with its appearance count, total screen time and first and last appearance in each
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path   string  true   "Character ID"
+// @Param        page       query  int     false  "Page number"
+// @Param        page_size  query  int     false  "Page size"
+// @Success      200  {object}  common.Response{data=character.CharacterVideoListResponse}  "Videos of the character"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Character not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/{id}/videos [get]
+func (h *Handler) GetCharacterVideos(c *gin.Context) {
+	var queryParams character.CharacterVideoFilterAndPagination
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.GetCharacterVideos(c.Param("id"), queryParams, viewer)
+	if err != nil {
+		h.writeCharacterError(c, err, "Failed to retrieve the videos of the character")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Character videos retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (h *Handler) writeCharacterError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrInvalidCharacter):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCharacterForbidden):
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCharacterNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCharacterAlreadyExists),
+		errors.Is(err, common.ErrCharacterInUse):
+		c.JSON(http.StatusConflict, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package character
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetVideoCoOccurrence godoc
+// @Summary      Get the character co-occurrence matrix of a video
+// @Description  Compute, from the overlaps of character appearances, how long each pair of characters of a video is on screen together, in how many scenes, and the Jaccard index of their screen times. format=graphml or format=gexf returns the matrix as a graph file for network tools
+// @Tags         characters
+// @Produce      json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        video_id  path      string  true  "Video ID"
+// @Param        min_confidence query number false "Minimum character confidence; detections below it are ignored"
+// @Param        overlap_threshold query number false "Longest absence in seconds bridged when counting shared scenes (default: 1.0 seconds)"
+// @Param        min_shared_seconds query number false "Leave out the pairs on screen together for less than this many seconds"
+// @Param        format    query     string  false "Output format: json (default), graphml or gexf"
+// @Success      200  {object}  common.Response{data=character.CoOccurrenceMatrix}  "Co-occurrence matrix retrieved successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{video_id}/co-occurrence [get]
+func (h *Handler) GetVideoCoOccurrence(c *gin.Context) {
+	var params character.CoOccurrenceParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	matrix, err := h.service.Character.GetVideoCoOccurrence(c.Param("id"), params, viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidUUID):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video ID format",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoNotFound):
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to get video co-occurrence: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to compute co-occurrence matrix",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	h.writeCoOccurrence(c, matrix, params.Format)
+}
+
+// GetCoOccurrence godoc
+// @Summary      Get the character co-occurrence matrix of a set of videos
+// @Description  Compute the character co-occurrence matrix summed over the visible videos with appearances, selected by video_ids and the tag filters of the video listing. At least one of video_ids, tag_ids, tag_codes or tag_ranges is required and at most 500 videos may match. format=graphml or format=gexf returns the matrix as a graph file for network tools
+// @Tags         characters
+// @Produce      json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        video_ids query     []string false "Video IDs to aggregate over"
+// @Param        tag_ids            query  []int     false  "Tag IDs; ORed within a category, ANDed across categories"  collectionFormat(multi)
+// @Param        tag_codes          query  []string  false  "Tag codes, same semantics as tag_ids"  collectionFormat(multi)
+// @Param        exclude_tag_ids    query  []int     false  "Exclude videos with any of these tags"  collectionFormat(multi)
+// @Param        exclude_tag_codes  query  []string  false  "Exclude videos with any of these tag codes"  collectionFormat(multi)
+// @Param        tag_ranges         query  []string  false  "Range category filters, category_code:min:max"  collectionFormat(multi)
+// @Param        min_confidence query number false "Minimum character confidence; detections below it are ignored"
+// @Param        overlap_threshold query number false "Longest absence in seconds bridged when counting shared scenes (default: 1.0 seconds)"
+// @Param        min_shared_seconds query number false "Leave out the pairs on screen together for less than this many seconds"
+// @Param        format    query     string  false "Output format: json (default), graphml or gexf"
+// @Success      200  {object}  common.Response{data=character.CoOccurrenceMatrix}  "Co-occurrence matrix retrieved successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/co-occurrence [get]
+func (h *Handler) GetCoOccurrence(c *gin.Context) {
+	var filter character.CoOccurrenceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	for _, idStr := range filter.VideoIDsStr {
+		for _, part := range strings.Split(idStr, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			videoID, err := uuid.Parse(part)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, common.Response{
+					Message:     "Invalid video UUID",
+					ErrorDetail: "Video ID '" + part + "' is not a valid UUID",
+				})
+				return
+			}
+			filter.VideoIDs = append(filter.VideoIDs, videoID)
+		}
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	matrix, err := h.service.Character.GetCoOccurrence(filter, viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidCoOccurrence), errors.Is(err, common.ErrInvalidTagRange):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to get co-occurrence: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to compute co-occurrence matrix",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	h.writeCoOccurrence(c, matrix, filter.Format)
+}
+
+// writeCoOccurrence writes matrix in the requested format: the usual JSON
+// response, or a GraphML or GEXF file.
+func (h *Handler) writeCoOccurrence(c *gin.Context, matrix *character.CoOccurrenceMatrix, format string) {
+	var doc interface{}
+	var contentType, extension string
+	switch format {
+	case character.CoOccurrenceFormatGraphML:
+		doc, contentType, extension = coOccurrenceGraphML(matrix), "application/graphml+xml", "graphml"
+	case character.CoOccurrenceFormatGEXF:
+		doc, contentType, extension = coOccurrenceGEXF(matrix), "application/gexf+xml", "gexf"
+	default:
+		c.JSON(http.StatusOK, common.Response{
+			Message: "Co-occurrence matrix retrieved successfully",
+			Data:    matrix,
+		})
+		return
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		h.logger.Error("Failed to encode co-occurrence matrix: " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     "Failed to encode co-occurrence matrix",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="co-occurrence.%s"`, extension))
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// GraphML document, see http://graphml.graphdrawing.org.
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func coOccurrenceGraphML(m *character.CoOccurrenceMatrix) graphML {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "screen_time", For: "node", Name: "screen_time", Type: "double"},
+			{ID: "node_video_count", For: "node", Name: "video_count", Type: "int"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+			{ID: "shared_seconds", For: "edge", Name: "shared_seconds", Type: "double"},
+			{ID: "shared_scenes", For: "edge", Name: "shared_scenes", Type: "int"},
+			{ID: "jaccard", For: "edge", Name: "jaccard", Type: "double"},
+			{ID: "edge_video_count", For: "edge", Name: "video_count", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "co-occurrence", EdgeDefault: "undirected"},
+	}
+	for _, c := range m.Characters {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: c.CharacterID.String(),
+			Data: []graphMLData{
+				{Key: "name", Value: c.CharacterName},
+				{Key: "screen_time", Value: formatFloat(c.ScreenTime)},
+				{Key: "node_video_count", Value: strconv.Itoa(c.VideoCount)},
+			},
+		})
+	}
+	for i, p := range m.Pairs {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: p.CharacterID.String(),
+			Target: p.OtherCharacterID.String(),
+			Data: []graphMLData{
+				{Key: "weight", Value: formatFloat(p.SharedSeconds)},
+				{Key: "shared_seconds", Value: formatFloat(p.SharedSeconds)},
+				{Key: "shared_scenes", Value: strconv.Itoa(p.SharedScenes)},
+				{Key: "jaccard", Value: formatFloat(p.Jaccard)},
+				{Key: "edge_video_count", Value: strconv.Itoa(p.VideoCount)},
+			},
+		})
+	}
+	return doc
+}
+
+// GEXF 1.3 document, see https://gexf.net.
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	Mode            string           `xml:"mode,attr"`
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Weight    string         `xml:"weight,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func coOccurrenceGEXF(m *character.CoOccurrenceMatrix) gexf {
+	doc := gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			Mode:            "static",
+			DefaultEdgeType: "undirected",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{
+					{ID: "screen_time", Title: "screen_time", Type: "double"},
+					{ID: "video_count", Title: "video_count", Type: "integer"},
+				}},
+				{Class: "edge", Attributes: []gexfAttribute{
+					{ID: "shared_seconds", Title: "shared_seconds", Type: "double"},
+					{ID: "shared_scenes", Title: "shared_scenes", Type: "integer"},
+					{ID: "jaccard", Title: "jaccard", Type: "double"},
+					{ID: "video_count", Title: "video_count", Type: "integer"},
+				}},
+			},
+		},
+	}
+	for _, c := range m.Characters {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    c.CharacterID.String(),
+			Label: c.CharacterName,
+			AttValues: []gexfAttValue{
+				{For: "screen_time", Value: formatFloat(c.ScreenTime)},
+				{For: "video_count", Value: strconv.Itoa(c.VideoCount)},
+			},
+		})
+	}
+	for i, p := range m.Pairs {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:     strconv.Itoa(i),
+			Source: p.CharacterID.String(),
+			Target: p.OtherCharacterID.String(),
+			Weight: formatFloat(p.SharedSeconds),
+			AttValues: []gexfAttValue{
+				{For: "shared_seconds", Value: formatFloat(p.SharedSeconds)},
+				{For: "shared_scenes", Value: strconv.Itoa(p.SharedScenes)},
+				{For: "jaccard", Value: formatFloat(p.Jaccard)},
+				{For: "video_count", Value: strconv.Itoa(p.VideoCount)},
+			},
+		})
+	}
+	return doc
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
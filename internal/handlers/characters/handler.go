@@ -1,6 +1,7 @@
 package character
 
 import (
+	"errors"
 	"net/http"
 	"smart-scene-app-api/common"
 	"smart-scene-app-api/internal/models/character"
@@ -12,7 +13,7 @@ import (
 
 // GetCharactersByVideoID godoc
 // @Summary      Get characters by video ID
-// @Description  Retrieve a list of characters that appear in a specific video with appearance statistics
+// @Description  Retrieve the characters that appear in a specific video with appearance statistics: appearance count, screen time (overlaps counted once), first/last appearance, average/max confidence and share of the video's runtime
 // @Tags         characters
 // @Accept       json
 // @Produce      json
@@ -20,13 +21,14 @@ import (
 // @Param        video_id  path      string  true  "Video ID"
 // @Param        page      query     int     false "Page number (default: 1)"
 // @Param        page_size query     int     false "Page size (default: 10, max: 100)"
-// @Param        character_name query string false "Filter by character name"
-// @Param        min_confidence query number false "Minimum confidence threshold"
+// @Param        character_name query string false "Filter by character name (contains, case-insensitive)"
+// @Param        min_confidence query number false "Minimum confidence threshold; appearances below it are not counted"
 // @Param        min_appearances query int   false "Minimum number of appearances"
-// @Param        sort      query     string  false "Sort by: appearance_count.desc, total_duration.desc, first_appearance.asc, character_name.asc, confidence.desc"
+// @Param        sort      query     string  false "Sort by: appearance_count, total_duration, first_appearance, last_appearance, character_name, confidence, max_confidence, screen_time_share, each .asc or .desc (default: appearance_count.desc)"
 // @Success      200  {object}  common.Response{data=character.VideoCharacterListResponse}  "Characters retrieved successfully"
 // @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
 // @Failure      500  {object}  common.Response  "Internal server error"
 // @Router       /api/v1/videos/{video_id}/characters [get]
 func (h *Handler) GetCharactersByVideoID(c *gin.Context) {
@@ -48,8 +50,24 @@ func (h *Handler) GetCharactersByVideoID(c *gin.Context) {
 		return
 	}
 
-	characters, err := h.service.Character.GetCharactersByVideoID(videoID, queryParams)
+	viewer, err := common.ViewerFromJwt(c)
 	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	characters, err := h.service.Character.GetCharactersByVideoID(videoID, queryParams, viewer)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		if err == common.ErrInvalidUUID {
 			c.JSON(http.StatusBadRequest, common.Response{
 				Message:     "Invalid video ID format",
@@ -57,6 +75,13 @@ func (h *Handler) GetCharactersByVideoID(c *gin.Context) {
 			})
 			return
 		}
+		if err == common.ErrVideoNotFound {
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		h.logger.Error("Failed to get characters by video ID: " + err.Error())
 		c.JSON(http.StatusInternalServerError, common.Response{
 			Message:     "Failed to retrieve characters",
@@ -85,11 +110,12 @@ func (h *Handler) GetCharactersByVideoID(c *gin.Context) {
 // @Param        exclude_characters query []string false "Character IDs that must NOT be present in scene"
 // @Param        min_duration query number false "Minimum scene duration in seconds"
 // @Param        max_duration query number false "Maximum scene duration in seconds"
-// @Param        min_confidence query number false "Minimum character confidence threshold"
-// @Param        overlap_threshold query number false "Time overlap threshold for grouping scenes (default: 1.0 seconds)"
+// @Param        min_confidence query number false "Minimum character confidence; detections below it are ignored"
+// @Param        overlap_threshold query number false "Longest absence in seconds bridged when merging appearances into scenes (default: 1.0 seconds)"
 // @Success      200  {object}  common.Response{data=character.VideoSceneListResponse}  "Scenes retrieved successfully"
 // @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
 // @Failure      500  {object}  common.Response  "Internal server error"
 // @Router       /api/v1/videos/{video_id}/scenes [get]
 func (h *Handler) GetVideoScenesWithCharacters(c *gin.Context) {
@@ -141,8 +167,24 @@ func (h *Handler) GetVideoScenesWithCharacters(c *gin.Context) {
 		}
 	}
 
-	scenes, err := h.service.Character.GetVideoScenesWithCharacters(videoID, queryParams)
+	viewer, err := common.ViewerFromJwt(c)
 	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	scenes, err := h.service.Character.GetVideoScenesWithCharacters(videoID, queryParams, viewer)
+	if err != nil {
+		if err == common.ErrInvalidSceneFilter {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		if err == common.ErrInvalidUUID {
 			c.JSON(http.StatusBadRequest, common.Response{
 				Message:     "Invalid video ID format",
@@ -150,6 +192,13 @@ func (h *Handler) GetVideoScenesWithCharacters(c *gin.Context) {
 			})
 			return
 		}
+		if err == common.ErrVideoNotFound {
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		h.logger.Error("Failed to get video scenes: " + err.Error())
 		c.JSON(http.StatusInternalServerError, common.Response{
 			Message:     "Failed to retrieve video scenes",
@@ -0,0 +1,121 @@
+package character
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MergeCharacters godoc
+// @Summary      Merge characters
+// @Description  Merge duplicate characters into the target: their appearances and video tags are moved to it in a single transaction, their metadata is combined under the target's and they are deactivated, or deleted with delete_sources. Video tags the target already has on the same video are dropped. With dry_run the affected rows are reported without changing anything. The returned merge_id can be used to undo the merge. Admin only.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                            true  "Target character ID"
+// @Param        request  body      character.MergeCharactersRequest  true  "Characters to merge"
+// @Success      200  {object}  common.Response{data=character.CharacterMergeResponse}  "Characters merged successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Character not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/{id}/merge [post]
+func (h *Handler) MergeCharacters(c *gin.Context) {
+	var req character.MergeCharactersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid merge request",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.MergeCharacters(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeMergeError(c, err, "Failed to merge characters")
+		return
+	}
+
+	message := "Characters merged successfully"
+	if result.DryRun {
+		message = "Dry run: no characters were merged"
+	}
+	c.JSON(http.StatusOK, common.Response{
+		Message: message,
+		Data:    result,
+	})
+}
+
+// UndoCharacterMerge godoc
+// @Summary      Undo a character merge
+// @Description  Revert a merge: the sources are restored, and the appearances and video tags moved to the target return to them. Admin only.
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        merge_id  path      string  true  "Merge ID"
+// @Success      200  {object}  common.Response{data=character.CharacterMergeResponse}  "Merge undone successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Merge not found"
+// @Failure      409  {object}  common.Response  "Merge already undone"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/characters/merges/{merge_id}/undo [post]
+func (h *Handler) UndoCharacterMerge(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Character.UndoCharacterMerge(c.Param("merge_id"), viewer)
+	if err != nil {
+		h.writeMergeError(c, err, "Failed to undo character merge")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Merge undone successfully",
+		Data:    result,
+	})
+}
+
+func (h *Handler) writeMergeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidCharacterMerge):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCharacterMergeNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCharacterMergeUndone):
+		c.JSON(http.StatusConflict, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.writeCharacterError(c, err, message)
+	}
+}
@@ -9,10 +9,33 @@ import (
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	v1 := router.Group("/api/v1")
 	{
+		characters := v1.Group("/characters")
+		{
+			characters.GET("", middleware.UserAuthentication(), h.GetCharacters)
+			characters.POST("", middleware.UserAuthentication(), h.CreateCharacter)
+			characters.GET("/co-occurrence", middleware.UserAuthentication(), h.GetCoOccurrence)
+			characters.GET("/:id", middleware.UserAuthentication(), h.GetCharacter)
+			characters.PUT("/:id", middleware.UserAuthentication(), h.UpdateCharacter)
+			characters.DELETE("/:id", middleware.UserAuthentication(), h.DeleteCharacter)
+			characters.GET("/:id/videos", middleware.UserAuthentication(), h.GetCharacterVideos)
+			characters.POST("/:id/merge", middleware.UserAuthentication(), h.MergeCharacters)
+			characters.POST("/merges/:merge_id/undo", middleware.UserAuthentication(), h.UndoCharacterMerge)
+		}
+
 		videos := v1.Group("/videos")
 		{
 			videos.GET("/:id/characters", middleware.UserAuthentication(), h.GetCharactersByVideoID)
 			videos.GET("/:id/scenes", middleware.UserAuthentication(), h.GetVideoScenesWithCharacters)
+			videos.POST("/:id/scenes/query", middleware.UserAuthentication(), h.QueryVideoScenes)
+			videos.GET("/:id/co-occurrence", middleware.UserAuthentication(), h.GetVideoCoOccurrence)
+			videos.PUT("/:id/appearances", middleware.UserAuthentication(), h.ReplaceVideoAppearances)
+			videos.GET("/:id/appearances", middleware.UserAuthentication(), h.GetVideoAppearances)
+			videos.POST("/:id/appearances", middleware.UserAuthentication(), h.CreateAppearance)
+			videos.POST("/:id/appearances/merge", middleware.UserAuthentication(), h.MergeAppearances)
+			videos.PUT("/:id/appearances/:appearance_id", middleware.UserAuthentication(), h.UpdateAppearance)
+			videos.DELETE("/:id/appearances/:appearance_id", middleware.UserAuthentication(), h.DeleteAppearance)
+			videos.POST("/:id/appearances/:appearance_id/split", middleware.UserAuthentication(), h.SplitAppearance)
+			videos.POST("/:id/appearances/:appearance_id/review", middleware.UserAuthentication(), h.ReviewAppearance)
 		}
 	}
 }
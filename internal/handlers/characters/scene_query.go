@@ -0,0 +1,90 @@
+package character
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	"smart-scene-app-api/internal/models/character"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryVideoScenes godoc
+// @Summary      Query video scenes with a character expression
+// @Description  Retrieve the scenes of a video where a boolean expression over its characters holds. Operators: character (by character_id or name), and, or, not, at_least (with n) and cast (exactly these characters and nobody else)
+// @Tags         characters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        video_id  path      string  true  "Video ID"
+// @Param        page      query     int     false "Page number (default: 1)"
+// @Param        page_size query     int     false "Page size (default: 10, max: 100)"
+// @Param        request   body      character.SceneQueryRequest  true  "Scene expression and thresholds"
+// @Success      200  {object}  common.Response{data=character.VideoSceneListResponse}  "Scenes retrieved successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{video_id}/scenes/query [post]
+func (h *Handler) QueryVideoScenes(c *gin.Context) {
+	var page models.BaseRequestParamsUri
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	var req character.SceneQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid request body",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	scenes, err := h.service.Character.QueryVideoScenes(c.Param("id"), req, page, viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidSceneExpression), errors.Is(err, common.ErrInvalidSceneFilter):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid scene query",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrInvalidUUID):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video ID format",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoNotFound):
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to query video scenes: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to query video scenes",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video scenes retrieved successfully",
+		Data:    scenes,
+	})
+}
@@ -0,0 +1,491 @@
+package collection
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/collection"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCollections godoc
+// @Summary      Get collections
+// @Description  Retrieve the collections visible to the caller: their own, organization-wide ones and those shared with them
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name       query  string  false  "Name contains"
+// @Param        owned      query  bool    false  "Only the caller's own collections"
+// @Param        page       query  int     false  "Page number"
+// @Param        page_size  query  int     false  "Page size"
+// @Param        sort       query  string  false  "Sort fields: created_at, updated_at, name; defaults to updated_at.desc"
+// @Success      200  {object}  common.Response{data=collection.CollectionListResponse}  "List of collections"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections [get]
+func (h *Handler) GetCollections(c *gin.Context) {
+	var queryParams collection.CollectionFilterAndPagination
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	collections, err := h.service.Collection.GetCollections(queryParams, viewer)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		h.logger.Error("Failed to get collections: " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     "Failed to retrieve collections",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Collections retrieved successfully",
+		Data:    collections,
+	})
+}
+
+// GetCollection godoc
+// @Summary      Get a collection
+// @Description  Retrieve a collection with its items in order. Items whose video was deleted or is no longer visible are flagged as stale with a warning.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Collection ID"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Collection details"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id} [get]
+func (h *Handler) GetCollection(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.GetCollection(c.Param("id"), viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to retrieve collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Collection retrieved successfully",
+		Data:    result,
+	})
+}
+
+// CreateCollection godoc
+// @Summary      Create a collection
+// @Description  Create a collection owned by the caller, optionally with initial items. Items are whole videos, or scenes with start_time and end_time. Visibility defaults to private.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      collection.CreateCollectionRequest  true  "Collection"
+// @Success      201  {object}  common.Response{data=collection.CollectionResponse}  "Collection created successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections [post]
+func (h *Handler) CreateCollection(c *gin.Context) {
+	var req collection.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid collection data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.CreateCollection(req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to create collection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Collection created successfully",
+		Data:    result,
+	})
+}
+
+// UpdateCollection godoc
+// @Summary      Update a collection
+// @Description  Rename a collection or change its description. Requires edit access.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                              true  "Collection ID"
+// @Param        request  body      collection.UpdateCollectionRequest  true  "Fields to update"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Collection updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id} [put]
+func (h *Handler) UpdateCollection(c *gin.Context) {
+	var req collection.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid collection data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.UpdateCollection(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to update collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Collection updated successfully",
+		Data:    result,
+	})
+}
+
+// DeleteCollection godoc
+// @Summary      Delete a collection
+// @Description  Delete a collection with its items and shares; only its owner or an admin can. Videos are not affected.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Collection ID"
+// @Success      204  {object}  common.Response  "Collection deleted successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id} [delete]
+func (h *Handler) DeleteCollection(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Collection.DeleteCollection(c.Param("id"), viewer); err != nil {
+		h.writeCollectionError(c, err, "Failed to delete collection")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, common.Response{
+		Message: "Collection deleted successfully",
+	})
+}
+
+// DuplicateCollection godoc
+// @Summary      Duplicate a collection
+// @Description  Copy a visible collection and its items into a new private collection owned by the caller. Shares are not copied.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                 true   "Collection ID"
+// @Param        request  body      collection.DuplicateCollectionRequest  false  "Name of the copy"
+// @Success      201  {object}  common.Response{data=collection.CollectionResponse}  "Collection duplicated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/duplicate [post]
+func (h *Handler) DuplicateCollection(c *gin.Context) {
+	var req collection.DuplicateCollectionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid collection data",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.DuplicateCollection(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to duplicate collection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Collection duplicated successfully",
+		Data:    result,
+	})
+}
+
+// AddCollectionItems godoc
+// @Summary      Add items to a collection
+// @Description  Insert videos or scenes at position, or at the end. The caller must be able to see every referenced video.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                true  "Collection ID"
+// @Param        request  body      collection.AddCollectionItemsRequest  true  "Items"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Items added successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/items [post]
+func (h *Handler) AddCollectionItems(c *gin.Context) {
+	var req collection.AddCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid item data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.AddItems(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to add items")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Items added successfully",
+		Data:    result,
+	})
+}
+
+// UpdateCollectionItem godoc
+// @Summary      Update a collection item
+// @Description  Change the scene range or note of an item; whole_video clears the range
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                  true  "Collection ID"
+// @Param        item_id  path      string                                  true  "Item ID"
+// @Param        request  body      collection.UpdateCollectionItemRequest  true  "Fields to update"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Item updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection or item not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/items/{item_id} [put]
+func (h *Handler) UpdateCollectionItem(c *gin.Context) {
+	var req collection.UpdateCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid item data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.UpdateItem(c.Param("id"), c.Param("item_id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to update item")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Item updated successfully",
+		Data:    result,
+	})
+}
+
+// RemoveCollectionItem godoc
+// @Summary      Remove a collection item
+// @Description  Remove an item; the following items move up
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string  true  "Collection ID"
+// @Param        item_id  path      string  true  "Item ID"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Item removed successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection or item not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/items/{item_id} [delete]
+func (h *Handler) RemoveCollectionItem(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.RemoveItem(c.Param("id"), c.Param("item_id"), viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to remove item")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Item removed successfully",
+		Data:    result,
+	})
+}
+
+// ReorderCollectionItems godoc
+// @Summary      Reorder collection items
+// @Description  Set the order of the items of a collection; every item must be listed exactly once
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                    true  "Collection ID"
+// @Param        request  body      collection.ReorderCollectionItemsRequest  true  "Item IDs in the new order"
+// @Success      200  {object}  common.Response{data=collection.CollectionResponse}  "Items reordered successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/reorder [post]
+func (h *Handler) ReorderCollectionItems(c *gin.Context) {
+	var req collection.ReorderCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid order data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Collection.ReorderItems(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to reorder items")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Items reordered successfully",
+		Data:    result,
+	})
+}
+
+func (h *Handler) writeCollectionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrInvalidVisibility),
+		errors.Is(err, common.ErrInvalidVideoShare),
+		errors.Is(err, common.ErrInvalidCollectionItem),
+		errors.Is(err, common.ErrInvalidCollectionOrder):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCollectionForbidden):
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrCollectionNotFound),
+		errors.Is(err, common.ErrCollectionItemNotFound),
+		errors.Is(err, common.ErrCollectionShareNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package collection
+
+import (
+	"smart-scene-app-api/internal/services"
+	"smart-scene-app-api/middleware"
+	"smart-scene-app-api/server"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	sc      server.ServerContext
+	service *services.Service
+	logger  *zap.Logger
+}
+
+func NewHandler(sc server.ServerContext) *Handler {
+	return &Handler{
+		sc:      sc,
+		service: services.NewService(sc),
+		logger:  zap.NewExample(),
+	}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	protected := router.Group("/api/v1")
+	{
+		collections := protected.Group("/collections")
+		{
+			collections.GET("", middleware.UserAuthentication(), h.GetCollections)
+			collections.POST("", middleware.UserAuthentication(), h.CreateCollection)
+			collections.GET("/:id", middleware.UserAuthentication(), h.GetCollection)
+			collections.PUT("/:id", middleware.UserAuthentication(), h.UpdateCollection)
+			collections.DELETE("/:id", middleware.UserAuthentication(), h.DeleteCollection)
+			collections.POST("/:id/duplicate", middleware.UserAuthentication(), h.DuplicateCollection)
+
+			collections.POST("/:id/items", middleware.UserAuthentication(), h.AddCollectionItems)
+			collections.PUT("/:id/items/:item_id", middleware.UserAuthentication(), h.UpdateCollectionItem)
+			collections.DELETE("/:id/items/:item_id", middleware.UserAuthentication(), h.RemoveCollectionItem)
+			collections.POST("/:id/reorder", middleware.UserAuthentication(), h.ReorderCollectionItems)
+
+			collections.GET("/:id/sharing", middleware.UserAuthentication(), h.GetCollectionSharing)
+			collections.PUT("/:id/visibility", middleware.UserAuthentication(), h.SetCollectionVisibility)
+			collections.POST("/:id/shares", middleware.UserAuthentication(), h.ShareCollection)
+			collections.DELETE("/:id/shares/:share_id", middleware.UserAuthentication(), h.UnshareCollection)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package collection
+
+import (
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/video"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCollectionSharing godoc
+// @Summary      Get the sharing of a collection
+// @Description  Retrieve the visibility of a collection and the users and roles it is shared with. Only the owner or an admin can.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Collection ID"
+// @Success      200  {object}  common.Response{data=collection.CollectionSharingResponse}  "Collection sharing"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/sharing [get]
+func (h *Handler) GetCollectionSharing(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Collection.GetCollectionSharing(c.Param("id"), viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to retrieve collection sharing")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Collection sharing retrieved successfully",
+		Data:    sharing,
+	})
+}
+
+// SetCollectionVisibility godoc
+// @Summary      Set the visibility of a collection
+// @Description  private: owner only. shared: owner and the users and roles it is shared with. organization: every user can view; shares grant editing.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "Collection ID"
+// @Param        request  body      video.VideoVisibilityRequest  true  "Visibility"
+// @Success      200  {object}  common.Response{data=collection.CollectionSharingResponse}  "Visibility updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/visibility [put]
+func (h *Handler) SetCollectionVisibility(c *gin.Context) {
+	var req video.VideoVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid visibility data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Collection.SetCollectionVisibility(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to update visibility")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Visibility updated successfully",
+		Data:    sharing,
+	})
+}
+
+// ShareCollection godoc
+// @Summary      Share a collection
+// @Description  Grant a user or every user of a role viewer or editor access to a collection. Sharing again with the same user or role replaces the permission.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                   true  "Collection ID"
+// @Param        request  body      video.VideoShareRequest  true  "Share"
+// @Success      200  {object}  common.Response{data=collection.CollectionSharingResponse}  "Collection shared successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/shares [post]
+func (h *Handler) ShareCollection(c *gin.Context) {
+	var req video.VideoShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid share data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Collection.ShareCollection(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to share collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Collection shared successfully",
+		Data:    sharing,
+	})
+}
+
+// UnshareCollection godoc
+// @Summary      Remove a share of a collection
+// @Description  Revoke the access granted by a share
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      string  true  "Collection ID"
+// @Param        share_id  path      string  true  "Share ID"
+// @Success      200  {object}  common.Response{data=collection.CollectionSharingResponse}  "Share removed successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Collection or share not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/collections/{id}/shares/{share_id} [delete]
+func (h *Handler) UnshareCollection(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Collection.UnshareCollection(c.Param("id"), c.Param("share_id"), viewer)
+	if err != nil {
+		h.writeCollectionError(c, err, "Failed to remove share")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Share removed successfully",
+		Data:    sharing,
+	})
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/pkg/storage"
+	"smart-scene-app-api/server"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	sc     server.ServerContext
+	logger *zap.Logger
+}
+
+func NewHandler(sc server.ServerContext) *Handler {
+	return &Handler{
+		sc:     sc,
+		logger: zap.NewExample(),
+	}
+}
+
+// DownloadFile godoc
+// @Summary      Download a stored file
+// @Description  Serve an object of the local storage backend through a signed URL. Supports HTTP range requests.
+// @Tags         storage
+// @Produce      octet-stream
+// @Param        key        path      string  true  "Object key"
+// @Param        expires    query     int     true  "Expiry as unix timestamp"
+// @Param        signature  query     string  true  "URL signature"
+// @Success      200  {file}    file  "File content"
+// @Failure      403  {object}  common.Response  "Invalid or expired signature"
+// @Failure      404  {object}  common.Response  "File not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/storage/files/{key} [get]
+func (h *Handler) DownloadFile(c *gin.Context) {
+	local, ok := h.sc.Storage().(*storage.LocalStorage)
+	if !ok {
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     "File not found",
+			ErrorDetail: "local storage backend is not enabled",
+		})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.VerifySignature(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     "Access denied",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	info, err := local.StatObject(c.Request.Context(), key)
+	if err != nil {
+		h.abortWithStorageError(c, err)
+		return
+	}
+
+	body, err := local.GetObject(c.Request.Context(), key)
+	if err != nil {
+		h.abortWithStorageError(c, err)
+		return
+	}
+	defer body.Close()
+
+	if info.ContentType != "" {
+		c.Header("Content-Type", info.ContentType)
+	}
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, info.Key, info.LastModified, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, body, nil)
+}
+
+func (h *Handler) abortWithStorageError(c *gin.Context, err error) {
+	switch err {
+	case storage.ErrObjectNotFound, storage.ErrInvalidKey:
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     "File not found",
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error("Failed to read stored file: " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     "Failed to read file",
+			ErrorDetail: err.Error(),
+		})
+	}
+}
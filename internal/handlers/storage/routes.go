@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	public := router.Group("/api/v1")
+	{
+		// Access is granted by the URL signature, not by a user token.
+		public.GET("/storage/files/*key", h.DownloadFile)
+	}
+}
@@ -14,4 +14,12 @@ func RegisterTagRoutes(ctx server.ServerContext, router *gin.RouterGroup) {
 	{
 		tagRoutes.GET("/position/:position_code", middleware.UserAuthentication(), tagHandler.GetTagsByPosition)
 	}
+
+	videoTagRoutes := router.Group("/videos/:id/tags")
+	{
+		videoTagRoutes.GET("", middleware.UserAuthentication(), tagHandler.GetVideoTags)
+		videoTagRoutes.POST("", middleware.UserAuthentication(), tagHandler.AttachVideoTags)
+		videoTagRoutes.PUT("", middleware.UserAuthentication(), tagHandler.ReplaceVideoTags)
+		videoTagRoutes.DELETE("/:tag_id", middleware.UserAuthentication(), tagHandler.DetachVideoTag)
+	}
 }
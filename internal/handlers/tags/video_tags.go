@@ -0,0 +1,274 @@
+package tags
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	tagModels "smart-scene-app-api/internal/models/tag"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetVideoTags godoc
+// @Summary      Get tags of a video
+// @Description  List the tags attached to a video, including character-scoped tags
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Video ID"
+// @Success      200  {object}  common.Response{data=[]tagModels.VideoTagResponse}  "Video tags retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the video"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/tags [get]
+func (h *TagHandler) GetVideoTags(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	tags, version, err := h.tagService.GetVideoTags(c.Request.Context(), c.Param("id"), viewer)
+	if err != nil {
+		h.writeVideoTagError(c, err, "Failed to retrieve video tags")
+		return
+	}
+
+	c.Header("ETag", common.ETag(version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video tags retrieved successfully",
+		Data:    tags,
+	})
+}
+
+// AttachVideoTags godoc
+// @Summary      Attach tags to a video
+// @Description  Add tags to a video, optionally scoped to a character. Tags must be active and a single category allows only one tag per video.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Video ID"
+// @Param        request  body      tagModels.VideoTagsRequest  true  "Tags to attach"
+// @Success      200  {object}  common.Response{data=[]tagModels.VideoTagResponse}  "Tags attached successfully"
+// @Header       200  {string}  ETag  "New version of the video"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      409  {object}  common.Response  "Category allows only one tag"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/tags [post]
+func (h *TagHandler) AttachVideoTags(c *gin.Context) {
+	var req tagModels.VideoTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid tag data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	tags, version, err := h.tagService.AttachVideoTags(c.Request.Context(), c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeVideoTagError(c, err, "Failed to attach tags")
+		return
+	}
+
+	c.Header("ETag", common.ETag(version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Tags attached successfully",
+		Data:    tags,
+	})
+}
+
+// ReplaceVideoTags godoc
+// @Summary      Replace the tags of a video
+// @Description  Set the exact list of tags of a video; tags not in the list are detached
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Video ID"
+// @Param        If-Match  header   string                      false  "Video ETag the replacement is conditional on"
+// @Param        request  body      tagModels.VideoTagsRequest  true  "Complete tag list"
+// @Success      200  {object}  common.Response{data=[]tagModels.VideoTagResponse}  "Tags replaced successfully"
+// @Header       200  {string}  ETag  "New version of the video"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      409  {object}  common.Response  "Category allows only one tag"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/tags [put]
+func (h *TagHandler) ReplaceVideoTags(c *gin.Context) {
+	var req tagModels.VideoTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid tag data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	tags, version, err := h.tagService.ReplaceVideoTags(c.Request.Context(), c.Param("id"), req, ifMatch, viewer)
+	if err != nil {
+		h.writeVideoTagError(c, err, "Failed to replace tags")
+		return
+	}
+
+	c.Header("ETag", common.ETag(version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Tags replaced successfully",
+		Data:    tags,
+	})
+}
+
+// DetachVideoTag godoc
+// @Summary      Detach a tag from a video
+// @Description  Remove a tag from a video. With character_id only the attachment for that character is removed.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id            path      string  true   "Video ID"
+// @Param        tag_id        path      int     true   "Tag ID"
+// @Param        character_id  query     string  false  "Character ID"
+// @Param        If-Match      header    string  false  "Video ETag the removal is conditional on"
+// @Success      200  {object}  common.Response{data=[]tagModels.VideoTagResponse}  "Tag detached successfully"
+// @Header       200  {string}  ETag  "New version of the video"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or tag attachment not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/tags/{tag_id} [delete]
+func (h *TagHandler) DetachVideoTag(c *gin.Context) {
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid tag ID",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	var characterID *uuid.UUID
+	if raw := c.Query("character_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid character ID",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		characterID = &id
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	tags, version, err := h.tagService.DetachVideoTag(c.Request.Context(), c.Param("id"), tagID, characterID, ifMatch, viewer)
+	if err != nil {
+		h.writeVideoTagError(c, err, "Failed to detach tag")
+		return
+	}
+
+	c.Header("ETag", common.ETag(version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Tag detached successfully",
+		Data:    tags,
+	})
+}
+
+func (h *TagHandler) writeVideoTagError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrTagNotFound),
+		errors.Is(err, common.ErrTagInactive),
+		errors.Is(err, common.ErrCharacterNotFound):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoForbidden):
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoNotFound),
+		errors.Is(err, common.ErrVideoTagNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrTagCategorySingle):
+		c.JSON(http.StatusConflict, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
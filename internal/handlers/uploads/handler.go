@@ -0,0 +1,243 @@
+package upload
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/upload"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitiateUpload godoc
+// @Summary      Initiate a chunked video upload
+// @Description  Create an upload session. The file is then sent as numbered chunks of chunk_size bytes (only the last chunk may be shorter).
+// @Tags         uploads
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        upload  body      upload.InitiateUploadRequest  true  "Upload details"
+// @Success      201  {object}  common.Response{data=upload.UploadSession}  "Upload session created"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/uploads [post]
+func (h *Handler) InitiateUpload(c *gin.Context) {
+	var req upload.InitiateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid upload data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	session, err := h.service.Upload.InitiateUpload(req, userID)
+	if err != nil {
+		h.writeError(c, err, "Failed to initiate upload")
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Upload initiated successfully",
+		Data:    session,
+	})
+}
+
+// UploadPart godoc
+// @Summary      Upload a chunk
+// @Description  Upload one numbered chunk as the raw request body. Re-sending a chunk replaces it, so interrupted chunks can simply be retried.
+// @Tags         uploads
+// @Accept       octet-stream
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      string  true   "Upload ID"
+// @Param        part_number  path      int     true   "Chunk number, starting at 1"
+// @Param        X-Chunk-Checksum  header  string  false  "Hex encoded SHA-256 of the chunk"
+// @Success      200  {object}  common.Response{data=upload.UploadPart}  "Chunk received"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Upload not found"
+// @Failure      409  {object}  common.Response  "Upload is no longer active"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/uploads/{id}/parts/{part_number} [put]
+func (h *Handler) UploadPart(c *gin.Context) {
+	partNumber, err := strconv.Atoi(c.Param("part_number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid part number",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, upload.MaxChunkSize+1)
+	part, err := h.service.Upload.UploadPart(c.Param("id"), partNumber, body, c.GetHeader("X-Chunk-Checksum"), userID)
+	if err != nil {
+		h.writeError(c, err, "Failed to upload part")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Part uploaded successfully",
+		Data:    part,
+	})
+}
+
+// GetUploadStatus godoc
+// @Summary      Get upload progress
+// @Description  Return the upload session with received chunks, the contiguous received offset and the chunks still missing
+// @Tags         uploads
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Upload ID"
+// @Success      200  {object}  common.Response{data=upload.UploadStatusResponse}  "Upload status"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Upload not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/uploads/{id} [get]
+func (h *Handler) GetUploadStatus(c *gin.Context) {
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.service.Upload.GetUploadStatus(c.Param("id"), userID)
+	if err != nil {
+		h.writeError(c, err, "Failed to retrieve upload")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Upload retrieved successfully",
+		Data:    status,
+	})
+}
+
+// CompleteUpload godoc
+// @Summary      Complete an upload
+// @Description  Assemble all chunks into the final file and create the video in pending status
+// @Tags         uploads
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Upload ID"
+// @Success      201  {object}  common.Response{data=video.Video}  "Video created from upload"
+// @Failure      400  {object}  common.Response  "Upload is missing chunks"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Upload not found"
+// @Failure      409  {object}  common.Response  "Upload is no longer active"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/uploads/{id}/complete [post]
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	createdVideo, err := h.service.Upload.CompleteUpload(c.Param("id"), userID)
+	if err != nil {
+		h.writeError(c, err, "Failed to complete upload")
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.Response{
+		Message: "Upload completed successfully",
+		Data:    createdVideo,
+	})
+}
+
+// AbortUpload godoc
+// @Summary      Abort an upload
+// @Description  Cancel an upload session and discard the chunks received so far
+// @Tags         uploads
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Upload ID"
+// @Success      204  {object}  common.Response  "Upload aborted"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Upload not found"
+// @Failure      409  {object}  common.Response  "Upload is no longer active"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/uploads/{id} [delete]
+func (h *Handler) AbortUpload(c *gin.Context) {
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Upload.AbortUpload(c.Param("id"), userID); err != nil {
+		h.writeError(c, err, "Failed to abort upload")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, common.Response{
+		Message: "Upload aborted successfully",
+	})
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrInvalidUploadPart),
+		errors.Is(err, common.ErrUploadChecksumMismatch),
+		errors.Is(err, common.ErrUploadIncomplete),
+		errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     "Upload not found",
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrUploadNotActive):
+		c.JSON(http.StatusConflict, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package upload
+
+import (
+	"smart-scene-app-api/internal/services"
+	"smart-scene-app-api/middleware"
+	"smart-scene-app-api/server"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	sc      server.ServerContext
+	service *services.Service
+	logger  *zap.Logger
+}
+
+func NewHandler(sc server.ServerContext) *Handler {
+	return &Handler{
+		sc:      sc,
+		service: services.NewService(sc),
+		logger:  zap.NewExample(),
+	}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	protected := router.Group("/api/v1")
+	{
+		uploads := protected.Group("/uploads")
+		{
+			uploads.POST("", middleware.UserAuthentication(), h.InitiateUpload)
+			uploads.GET("/:id", middleware.UserAuthentication(), h.GetUploadStatus)
+			uploads.PUT("/:id/parts/:part_number", middleware.UserAuthentication(), h.UploadPart)
+			uploads.POST("/:id/complete", middleware.UserAuthentication(), h.CompleteUpload)
+			uploads.DELETE("/:id", middleware.UserAuthentication(), h.AbortUpload)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"errors"
 	"net/http"
 	"smart-scene-app-api/common"
 	"smart-scene-app-api/internal/models/video"
@@ -10,12 +11,27 @@ import (
 
 // GetAllVideos godoc
 // @Summary      Get all videos
-// @Description  Retrieve a list of all videos
+// @Description  Retrieve the videos visible to the caller. With q, videos are searched by title, tags, characters and metadata, ranked by relevance and returned with highlighted snippets.
 // @Tags         videos
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {object}  common.Response{data=[]video.Video}  "List of videos"
+// @Param        q                  query  string    false  "Full-text query (web search syntax)"
+// @Param        title              query  string    false  "Title contains"
+// @Param        status             query  string    false  "Video status"
+// @Param        tag_ids            query  []int     false  "Tag IDs; ORed within a category, ANDed across categories"  collectionFormat(multi)
+// @Param        tag_codes          query  []string  false  "Tag codes, same semantics as tag_ids"  collectionFormat(multi)
+// @Param        exclude_tag_ids    query  []int     false  "Exclude videos with any of these tags"  collectionFormat(multi)
+// @Param        exclude_tag_codes  query  []string  false  "Exclude videos with any of these tag codes"  collectionFormat(multi)
+// @Param        tag_ranges         query  []string  false  "Range category filters, category_code:min:max"  collectionFormat(multi)
+// @Param        facets             query  bool      false  "Return tag, status and character counts in extra (video.VideoFacets)"
+// @Param        page               query  int       false  "Page number"
+// @Param        page_size          query  int       false  "Page size"
+// @Param        pagination         query  string    false  "cursor for keyset pagination; total and page are then not computed"  Enums(cursor)
+// @Param        cursor             query  string    false  "next_cursor or prev_cursor of a previous response"
+// @Param        sort               query  string    false  "Sort fields: created_at, updated_at, title, duration, status, character_count, each with .asc/.desc and .nulls_first/.nulls_last, e.g. duration.desc,title; defaults to relevance when q is set, else created_at.desc"
+// @Success      200  {object}  common.Response{data=video.VideoListResponse}  "List of videos"
+// @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
 // @Failure      500  {object}  common.Response  "Internal server error"
 // @Router       /api/v1/videos [get]
@@ -29,8 +45,24 @@ func (h *Handler) GetAllVideos(c *gin.Context) {
 		return
 	}
 
-	videos, err := h.service.Video.GetAllVideos(queryParams)
+	viewer, err := common.ViewerFromJwt(c)
 	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	videos, err := h.service.Video.GetAllVideos(queryParams, viewer)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTagRange) || errors.Is(err, common.ErrInvalidCursor) || errors.Is(err, common.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid query parameters",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		h.logger.Error("Failed to get videos: " + err.Error())
 		c.JSON(http.StatusInternalServerError, common.Response{
 			Message:     "Failed to retrieve videos",
@@ -54,6 +86,7 @@ func (h *Handler) GetAllVideos(c *gin.Context) {
 // @Security     BearerAuth
 // @Param        id  path      string  true  "Video ID"
 // @Success      200  {object}  common.Response{data=video.Video}  "Video details"
+// @Header       200  {string}  ETag  "Version of the video"
 // @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
 // @Failure      404  {object}  common.Response  "Video not found"
@@ -69,7 +102,16 @@ func (h *Handler) GetVideoDetail(c *gin.Context) {
 		return
 	}
 
-	video, err := h.service.Video.GetVideoDetail(videoID)
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	video, err := h.service.Video.GetVideoDetail(videoID, viewer)
 	if err != nil {
 		if err == common.ErrVideoNotFound {
 			c.JSON(http.StatusNotFound, common.Response{
@@ -86,6 +128,7 @@ func (h *Handler) GetVideoDetail(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", common.ETag(video.Version))
 	c.JSON(http.StatusOK, common.Response{
 		Message: "Video retrieved successfully",
 		Data:    video,
@@ -94,7 +137,7 @@ func (h *Handler) GetVideoDetail(c *gin.Context) {
 
 // CreateVideo godoc
 // @Summary      Create a new video
-// @Description  Create a new video with the provided details
+// @Description  Create a new video owned by the caller. Visibility defaults to private.
 // @Tags         videos
 // @Accept       json
 // @Produce      json
@@ -115,8 +158,26 @@ func (h *Handler) CreateVideo(c *gin.Context) {
 		return
 	}
 
+	userID, err := common.UserIDFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+	newVideo.CreatedBy = userID
+	newVideo.UpdatedBy = userID
+
 	createdVideo, err := h.service.Video.CreateVideo(newVideo)
 	if err != nil {
+		if err == common.ErrInvalidVisibility {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video data",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		h.logger.Error("Failed to create video: " + err.Error())
 		c.JSON(http.StatusInternalServerError, common.Response{
 			Message:     "Failed to create video",
@@ -133,17 +194,21 @@ func (h *Handler) CreateVideo(c *gin.Context) {
 
 // UpdateVideo godoc
 // @Summary      Update an existing video
-// @Description  Update a video by its ID with the provided details
+// @Description  Update a video by its ID with the provided details. Requires edit access; visibility is changed with PUT /videos/{id}/visibility.
 // @Tags         videos
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id  path      string  true  "Video ID"
+// @Param        If-Match  header  string  false  "ETag the update is conditional on"
 // @Param        video  body      video.Video  true  "Updated video details"
 // @Success      200  {object}  common.Response{data=video.Video}  "Video updated successfully"
+// @Header       200  {string}  ETag  "New version of the video"
 // @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
 // @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
 // @Failure      500  {object}  common.Response  "Internal server error"
 // @Router       /api/v1/videos/{id} [put]
 func (h *Handler) UpdateVideo(c *gin.Context) {
@@ -165,8 +230,40 @@ func (h *Handler) UpdateVideo(c *gin.Context) {
 		return
 	}
 
-	video, err := h.service.Video.UpdateVideo(videoID, updatedVideo)
+	viewer, err := common.ViewerFromJwt(c)
 	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	video, err := h.service.Video.UpdateVideo(videoID, updatedVideo, ifMatch, viewer)
+	if err != nil {
+		if err == common.ErrVersionConflict {
+			c.JSON(http.StatusPreconditionFailed, common.Response{
+				Message:     "Video has been modified",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		if err == common.ErrVideoForbidden {
+			c.JSON(http.StatusForbidden, common.Response{
+				Message:     "Not allowed to update this video",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		if err == common.ErrVideoNotFound {
 			c.JSON(http.StatusNotFound, common.Response{
 				Message:     "Video not found",
@@ -174,6 +271,13 @@ func (h *Handler) UpdateVideo(c *gin.Context) {
 			})
 			return
 		}
+		if err == common.ErrVideoStatusNotUpdatable {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Video status cannot be updated directly",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		h.logger.Error("Failed to update video: " + err.Error())
 		c.JSON(http.StatusInternalServerError, common.Response{
 			Message:     "Failed to update video",
@@ -182,6 +286,113 @@ func (h *Handler) UpdateVideo(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", common.ETag(video.Version))
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video updated successfully",
+		Data:    video,
+	})
+}
+
+// PatchVideo godoc
+// @Summary      Patch a video
+// @Description  Apply an RFC 7396 JSON merge patch to a video. Only title, thumbnail_url, duration, metadata, character_count and has_character_analysis can be patched; metadata is merged deeply and a null member deletes that key. Unlike PUT, zero values such as 0, false or "" are written. Requires edit access.
+// @Tags         videos
+// @Accept       application/merge-patch+json
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      string  true  "Video ID"
+// @Param        If-Match  header  string  false  "ETag the patch is conditional on"
+// @Param        patch  body      object  true  "Merge patch"
+// @Success      200  {object}  common.Response{data=video.Video}  "Video updated successfully"
+// @Header       200  {string}  ETag  "New version of the video"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      415  {object}  common.Response  "Unsupported media type"
+// @Failure      412  {object}  common.Response  "Video has been modified"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id} [patch]
+func (h *Handler) PatchVideo(c *gin.Context) {
+	videoID := c.Param("id")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Video ID is required",
+			ErrorDetail: "The 'id' parameter is missing or empty",
+		})
+		return
+	}
+
+	if contentType := c.ContentType(); contentType != "application/merge-patch+json" && contentType != gin.MIMEJSON {
+		c.JSON(http.StatusUnsupportedMediaType, common.Response{
+			Message:     "Unsupported media type",
+			ErrorDetail: "expected application/merge-patch+json",
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid video patch",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	video, err := h.service.Video.PatchVideo(videoID, patch, ifMatch, viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrVersionConflict):
+			c.JSON(http.StatusPreconditionFailed, common.Response{
+				Message:     "Video has been modified",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrInvalidPatch), errors.Is(err, common.ErrInvalidUUID):
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video patch",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoForbidden):
+			c.JSON(http.StatusForbidden, common.Response{
+				Message:     "Not allowed to update this video",
+				ErrorDetail: err.Error(),
+			})
+		case errors.Is(err, common.ErrVideoNotFound):
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to patch video: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to update video",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("ETag", common.ETag(video.Version))
 	c.JSON(http.StatusOK, common.Response{
 		Message: "Video updated successfully",
 		Data:    video,
@@ -189,17 +400,20 @@ func (h *Handler) UpdateVideo(c *gin.Context) {
 }
 
 // DeleteVideo godoc
-// @Summary      Delete a video
-// @Description  Delete a video by its ID
+// @Summary      Move a video to the trash
+// @Description  Soft-delete a video by its ID; only its owner or an admin can. It is hidden from listings and purged after the retention period unless restored.
 // @Tags         videos
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id  path      string  true  "Video ID"
+// @Param        If-Match  header  string  false  "ETag the deletion is conditional on"
 // @Success      204  {object}  common.Response  "Video deleted successfully"
 // @Failure      400  {object}  common.Response  "Bad request"
 // @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
 // @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      412  {object}  common.Response  "Video has been modified"
 // @Failure      500  {object}  common.Response  "Internal server error"
 // @Router       /api/v1/videos/{id} [delete]
 func (h *Handler) DeleteVideo(c *gin.Context) {
@@ -212,7 +426,46 @@ func (h *Handler) DeleteVideo(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Video.DeleteVideo(videoID); err != nil {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	ifMatch, err := common.IfMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid If-Match header",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Video.DeleteVideo(videoID, ifMatch, viewer); err != nil {
+		if err == common.ErrVersionConflict {
+			c.JSON(http.StatusPreconditionFailed, common.Response{
+				Message:     "Video has been modified",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		if err == common.ErrVideoForbidden {
+			c.JSON(http.StatusForbidden, common.Response{
+				Message:     "Not allowed to delete this video",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
+		if err == common.ErrInvalidUUID {
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video ID format",
+				ErrorDetail: err.Error(),
+			})
+			return
+		}
 		if err == common.ErrVideoNotFound {
 			c.JSON(http.StatusNotFound, common.Response{
 				Message:     "Video not found",
@@ -229,6 +482,6 @@ func (h *Handler) DeleteVideo(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusNoContent, common.Response{
-		Message: "Video deleted successfully",
+		Message: "Video moved to trash successfully",
 	})
 }
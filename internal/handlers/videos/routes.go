@@ -30,11 +30,22 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 		videos := protected.Group("/videos")
 		{
 			videos.GET("", middleware.UserAuthentication(), h.GetAllVideos)
+			videos.GET("/trash", middleware.UserAuthentication(), h.GetTrashVideos)
 			videos.GET("/:id", middleware.UserAuthentication(), h.GetVideoDetail)
 
 			videos.POST("", middleware.UserAuthentication(), h.CreateVideo)
 			videos.PUT("/:id", middleware.UserAuthentication(), h.UpdateVideo)
+			videos.PATCH("/:id", middleware.UserAuthentication(), h.PatchVideo)
 			videos.DELETE("/:id", middleware.UserAuthentication(), h.DeleteVideo)
+			videos.POST("/:id/restore", middleware.UserAuthentication(), h.RestoreVideo)
+
+			videos.POST("/:id/transitions", middleware.UserAuthentication(), h.TransitionVideoStatus)
+			videos.GET("/:id/status-history", middleware.UserAuthentication(), h.GetVideoStatusHistory)
+
+			videos.GET("/:id/sharing", middleware.UserAuthentication(), h.GetVideoSharing)
+			videos.PUT("/:id/visibility", middleware.UserAuthentication(), h.SetVideoVisibility)
+			videos.POST("/:id/shares", middleware.UserAuthentication(), h.ShareVideo)
+			videos.DELETE("/:id/shares/:share_id", middleware.UserAuthentication(), h.UnshareVideo)
 		}
 	}
 }
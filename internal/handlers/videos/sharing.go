@@ -0,0 +1,208 @@
+package video
+
+import (
+	"errors"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/video"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVideoSharing godoc
+// @Summary      Get the sharing of a video
+// @Description  Retrieve the visibility of a video and the users and roles it is shared with. Only the owner or an admin can.
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Video ID"
+// @Success      200  {object}  common.Response{data=video.VideoSharingResponse}  "Video sharing"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/sharing [get]
+func (h *Handler) GetVideoSharing(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Video.GetVideoSharing(c.Param("id"), viewer)
+	if err != nil {
+		h.writeSharingError(c, err, "Failed to retrieve video sharing")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video sharing retrieved successfully",
+		Data:    sharing,
+	})
+}
+
+// SetVideoVisibility godoc
+// @Summary      Set the visibility of a video
+// @Description  private: owner only. shared: owner and the users and roles it is shared with. organization: every user can view; shares grant editing.
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "Video ID"
+// @Param        request  body      video.VideoVisibilityRequest  true  "Visibility"
+// @Success      200  {object}  common.Response{data=video.VideoSharingResponse}  "Visibility updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/visibility [put]
+func (h *Handler) SetVideoVisibility(c *gin.Context) {
+	var req video.VideoVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid visibility data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Video.SetVideoVisibility(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeSharingError(c, err, "Failed to update visibility")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Visibility updated successfully",
+		Data:    sharing,
+	})
+}
+
+// ShareVideo godoc
+// @Summary      Share a video
+// @Description  Grant a user or every user of a role viewer or editor access to a video. Sharing again with the same user or role replaces the permission.
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                   true  "Video ID"
+// @Param        request  body      video.VideoShareRequest  true  "Share"
+// @Success      200  {object}  common.Response{data=video.VideoSharingResponse}  "Video shared successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/shares [post]
+func (h *Handler) ShareVideo(c *gin.Context) {
+	var req video.VideoShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid share data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Video.ShareVideo(c.Param("id"), req, viewer)
+	if err != nil {
+		h.writeSharingError(c, err, "Failed to share video")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video shared successfully",
+		Data:    sharing,
+	})
+}
+
+// UnshareVideo godoc
+// @Summary      Remove a share of a video
+// @Description  Revoke the access granted by a share
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      string  true  "Video ID"
+// @Param        share_id  path      string  true  "Share ID"
+// @Success      200  {object}  common.Response{data=video.VideoSharingResponse}  "Share removed successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video or share not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/shares/{share_id} [delete]
+func (h *Handler) UnshareVideo(c *gin.Context) {
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	sharing, err := h.service.Video.UnshareVideo(c.Param("id"), c.Param("share_id"), viewer)
+	if err != nil {
+		h.writeSharingError(c, err, "Failed to remove share")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Share removed successfully",
+		Data:    sharing,
+	})
+}
+
+func (h *Handler) writeSharingError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrInvalidVisibility),
+		errors.Is(err, common.ErrInvalidVideoShare):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoForbidden):
+		c.JSON(http.StatusForbidden, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoNotFound),
+		errors.Is(err, common.ErrVideoShareNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package video
+
+import (
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/video"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransitionVideoStatus godoc
+// @Summary      Transition video status
+// @Description  Move a video to a new status. Allowed transitions: pending→processing, processing→completed, processing→failed, failed→pending
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Video ID"
+// @Param        transition  body      video.VideoStatusTransitionRequest  true  "Target status and reason"
+// @Success      200  {object}  common.Response{data=video.Video}  "Video status updated successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      403  {object}  common.Response  "Forbidden"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      409  {object}  common.Response  "Transition not allowed from the current status"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/transitions [post]
+func (h *Handler) TransitionVideoStatus(c *gin.Context) {
+	videoID := c.Param("id")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Video ID is required",
+			ErrorDetail: "The 'id' parameter is missing or empty",
+		})
+		return
+	}
+
+	var req video.VideoStatusTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid transition data",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	updatedVideo, err := h.service.Video.TransitionVideoStatus(videoID, req, viewer)
+	if err != nil {
+		switch err {
+		case common.ErrInvalidUUID, common.ErrInvalidVideoStatus:
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid transition request",
+				ErrorDetail: err.Error(),
+			})
+		case common.ErrVideoNotFound:
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		case common.ErrVideoForbidden:
+			c.JSON(http.StatusForbidden, common.Response{
+				Message:     "Not allowed to transition this video",
+				ErrorDetail: err.Error(),
+			})
+		case common.ErrInvalidStatusTransition:
+			c.JSON(http.StatusConflict, common.Response{
+				Message:     "Status transition not allowed",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to transition video status: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to transition video status",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video status updated successfully",
+		Data:    updatedVideo,
+	})
+}
+
+// GetVideoStatusHistory godoc
+// @Summary      Get video status history
+// @Description  Retrieve every status transition of a video, oldest first
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Video ID"
+// @Success      200  {object}  common.Response{data=[]video.VideoStatusHistory}  "Status history"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not found"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/status-history [get]
+func (h *Handler) GetVideoStatusHistory(c *gin.Context) {
+	videoID := c.Param("id")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Video ID is required",
+			ErrorDetail: "The 'id' parameter is missing or empty",
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	history, err := h.service.Video.GetVideoStatusHistory(videoID, viewer)
+	if err != nil {
+		switch err {
+		case common.ErrInvalidUUID:
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video ID format",
+				ErrorDetail: err.Error(),
+			})
+		case common.ErrVideoNotFound:
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to get video status history: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to retrieve video status history",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video status history retrieved successfully",
+		Data:    history,
+	})
+}
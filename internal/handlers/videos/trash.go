@@ -0,0 +1,122 @@
+package video
+
+import (
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/video"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrashVideos godoc
+// @Summary      List trashed videos
+// @Description  Retrieve soft-deleted videos, most recently deleted first, with the time they will be purged. Non-admins only see their own videos.
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page        query     int     false  "Page number (default: 1)"
+// @Param        page_size   query     int     false  "Page size (default: 10)"
+// @Param        title       query     string  false  "Filter by title"
+// @Param        deleted_by  query     string  false  "Filter by the user who deleted the video"
+// @Success      200  {object}  common.Response{data=video.VideoTrashListResponse}  "Trashed videos retrieved successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/trash [get]
+func (h *Handler) GetTrashVideos(c *gin.Context) {
+	var queryParams video.VideoTrashFilterAndPagination
+	if err := c.ShouldBindQuery(&queryParams); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid query parameters",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	videos, err := h.service.Video.GetTrashVideos(queryParams, viewer)
+	if err != nil {
+		h.logger.Error("Failed to get trashed videos: " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     "Failed to retrieve trashed videos",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Trashed videos retrieved successfully",
+		Data:    videos,
+	})
+}
+
+// RestoreVideo godoc
+// @Summary      Restore a trashed video
+// @Description  Take a soft-deleted video out of the trash; only its owner or an admin can
+// @Tags         videos
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Video ID"
+// @Success      200  {object}  common.Response{data=video.Video}  "Video restored successfully"
+// @Failure      400  {object}  common.Response  "Bad request"
+// @Failure      401  {object}  common.Response  "Unauthorized"
+// @Failure      404  {object}  common.Response  "Video not in trash"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/videos/{id}/restore [post]
+func (h *Handler) RestoreVideo(c *gin.Context) {
+	videoID := c.Param("id")
+	if videoID == "" {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Video ID is required",
+			ErrorDetail: "The 'id' parameter is missing or empty",
+		})
+		return
+	}
+
+	viewer, err := common.ViewerFromJwt(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     "Unauthorized",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	restored, err := h.service.Video.RestoreVideo(videoID, viewer)
+	if err != nil {
+		switch err {
+		case common.ErrInvalidUUID:
+			c.JSON(http.StatusBadRequest, common.Response{
+				Message:     "Invalid video ID format",
+				ErrorDetail: err.Error(),
+			})
+		case common.ErrVideoNotInTrash:
+			c.JSON(http.StatusNotFound, common.Response{
+				Message:     "Video not found in trash",
+				ErrorDetail: err.Error(),
+			})
+		default:
+			h.logger.Error("Failed to restore video: " + err.Error())
+			c.JSON(http.StatusInternalServerError, common.Response{
+				Message:     "Failed to restore video",
+				ErrorDetail: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Video restored successfully",
+		Data:    restored,
+	})
+}
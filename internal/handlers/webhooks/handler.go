@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/webhook"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxEventSize bounds a delivery; completed events carry every detection of a video.
+const maxEventSize = 32 << 20
+
+// ReceiveEvent godoc
+// @Summary      Receive a detection worker event
+// @Description  Accept a signed progress, completed or failed event from an external detection worker. The X-Webhook-Signature header is the hex HMAC-SHA256 of "<timestamp>.<nonce>.<body>" with the integration secret. Redelivered and unknown events, and late events about unknown, trashed, completed or failed videos, are acknowledged without side effects.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        integration          path      string         true  "Integration name"
+// @Param        X-Webhook-Timestamp  header    int            true  "Unix timestamp of the delivery"
+// @Param        X-Webhook-Nonce      header    string         true  "Unique delivery nonce"
+// @Param        X-Webhook-Signature  header    string         true  "sha256=<hex HMAC>"
+// @Param        event                body      webhook.Event  true  "Event"
+// @Success      200  {object}  common.Response{data=webhook.DeliveryResult}  "Event acknowledged"
+// @Failure      400  {object}  common.Response  "Invalid event"
+// @Failure      401  {object}  common.Response  "Invalid signature"
+// @Failure      409  {object}  common.Response  "Replayed delivery or conflicting status"
+// @Failure      500  {object}  common.Response  "Internal server error"
+// @Router       /api/v1/webhooks/{integration} [post]
+func (h *Handler) ReceiveEvent(c *gin.Context) {
+	integration := c.Param("integration")
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxEventSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid webhook payload",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	delivery := webhook.Delivery{
+		Timestamp: c.GetHeader(webhook.HeaderTimestamp),
+		Nonce:     c.GetHeader(webhook.HeaderNonce),
+		Signature: c.GetHeader(webhook.HeaderSignature),
+	}
+	if err := h.service.Webhook.VerifyDelivery(integration, delivery, body); err != nil {
+		h.writeError(c, err, "Webhook delivery rejected")
+		return
+	}
+
+	var event webhook.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid webhook payload",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&event); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     "Invalid webhook payload",
+			ErrorDetail: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.Webhook.HandleEvent(integration, event)
+	if err != nil {
+		if rerr := h.service.Webhook.ReleaseDelivery(integration, delivery); rerr != nil {
+			h.logger.Error("Failed to release webhook delivery: " + rerr.Error())
+		}
+		h.writeError(c, err, "Failed to process webhook event")
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Response{
+		Message: "Event acknowledged",
+		Data:    result,
+	})
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, common.ErrWebhookUnknownIntegration),
+		errors.Is(err, common.ErrWebhookInvalidSignature),
+		errors.Is(err, common.ErrWebhookTimestampExpired):
+		c.JSON(http.StatusUnauthorized, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrInvalidWebhookEvent),
+		errors.Is(err, common.ErrInvalidUUID),
+		errors.Is(err, common.ErrInvalidAppearance),
+		errors.Is(err, common.ErrCharacterNotFound),
+		errors.Is(err, common.ErrCharacterInactive):
+		c.JSON(http.StatusBadRequest, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrVideoNotFound):
+		c.JSON(http.StatusNotFound, common.Response{
+			Message:     "Video not found",
+			ErrorDetail: err.Error(),
+		})
+	case errors.Is(err, common.ErrWebhookReplay),
+		errors.Is(err, common.ErrWebhookEventInProgress),
+		errors.Is(err, common.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, common.Response{
+			Message:     message,
+			ErrorDetail: err.Error(),
+		})
+	}
+}
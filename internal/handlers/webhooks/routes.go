@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"smart-scene-app-api/internal/services"
+	"smart-scene-app-api/server"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	sc      server.ServerContext
+	service *services.Service
+	logger  *zap.Logger
+}
+
+func NewHandler(sc server.ServerContext) *Handler {
+	return &Handler{
+		sc:      sc,
+		service: services.NewService(sc),
+		logger:  zap.NewExample(),
+	}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	public := router.Group("/api/v1")
+	{
+		// Deliveries are authenticated by their HMAC signature, not by a user token.
+		public.POST("/webhooks/:integration", h.ReceiveEvent)
+	}
+}
@@ -2,22 +2,18 @@ package models
 
 import (
 	"smart-scene-app-api/common"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// QuerySort is the sort of a listing as written in the query string (Ex:
+// http://example.com/messages?sort=created_at.desc,title.asc.nulls_last).
+// It is parsed against the SortColumns of the model, see Parse.
 type QuerySort struct {
 	Origin string
 }
 
-// Parse the query string to order string (Ex: http://example.com/messages?sort=created_at.asc,updated_at.acs
-// => order string: created_at asc,updated_at acs)
-func (s QuerySort) Parse() string {
-	return strings.ReplaceAll(s.Origin, ".", " ")
-}
-
 type QueryParams struct {
 	Limit  int
 	Offset int
@@ -26,18 +22,27 @@ type QueryParams struct {
 	Selected []string
 }
 
+const PaginationCursor = "cursor"
+
 type BaseRequestParamsUri struct {
 	Page     int    `form:"page"`
 	PageSize int    `form:"page_size"`
 	Sort     string `form:"sort"`
+	// Pagination "cursor" switches to keyset pagination; passing a cursor
+	// does too.
+	Pagination string `form:"pagination"`
+	Cursor     string `form:"cursor"`
 }
 
 type BaseListResponse struct {
-	Total    int         `json:"total"`
-	Page     int         `json:"page"`
-	PageSize int         `json:"page_size"`
-	Items    interface{} `json:"items"`
-	Extra    interface{} `json:"extra"`
+	// Total and Page are not computed in cursor mode.
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	Items      interface{} `json:"items"`
+	Extra      interface{} `json:"extra"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
 }
 
 type Base struct {
@@ -47,6 +52,10 @@ type Base struct {
 }
 
 
+func (b *BaseRequestParamsUri) UseCursor() bool {
+	return b.Pagination == PaginationCursor || b.Cursor != ""
+}
+
 func (b *BaseRequestParamsUri) VerifyPaging() {
 	if b.Page <= 0 {
 		b.Page = 1
@@ -3,13 +3,14 @@ package character
 import (
 	"smart-scene-app-api/common"
 	models "smart-scene-app-api/internal/models"
+	"smart-scene-app-api/internal/models/video"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CharacterAppearance struct {
-	ID          int         `json:"id" gorm:"primaryKey;autoIncrement"`
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey"`
 	CreatedAt   time.Time   `json:"created_at" gorm:"type:timestamp;not null;default:now()"`
 	CreatedBy   uuid.UUID   `json:"created_by" gorm:"type:uuid;not null;index"`
 	VideoID     uuid.UUID   `json:"video_id" gorm:"type:uuid;not null;index"`
@@ -21,15 +22,47 @@ type CharacterAppearance struct {
 	Duration    float64     `json:"duration" gorm:"type:decimal(10,3);default:0"`
 	Confidence  float64     `json:"confidence" gorm:"type:decimal(5,4);default:0"`
 	Metadata    common.JSON `json:"metadata" gorm:"type:jsonb"`
+	IsConfirmed bool        `json:"is_confirmed" gorm:"not null;default:false"`
+	IsRejected  bool        `json:"is_rejected" gorm:"not null;default:false"`
+	ReviewedBy  *uuid.UUID  `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt  *time.Time  `json:"reviewed_at,omitempty" gorm:"type:timestamp"`
 
-	Video     interface{} `json:"video,omitempty" gorm:"foreignKey:VideoID"`
-	Character *Character  `json:"character,omitempty" gorm:"foreignKey:CharacterID;references:ID"`
+	Video     *video.Video `json:"video,omitempty" gorm:"foreignKey:VideoID;references:ID"`
+	Character *Character   `json:"character,omitempty" gorm:"foreignKey:CharacterID;references:ID"`
 }
 
 func (c *CharacterAppearance) TableName() string {
 	return common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES
 }
 
+// AppearanceDetection is one detected appearance sent by the analysis pipeline.
+type AppearanceDetection struct {
+	CharacterID uuid.UUID   `json:"character_id" binding:"required"`
+	StartFrame  int         `json:"start_frame" binding:"gte=0"`
+	EndFrame    int         `json:"end_frame" binding:"gte=0"`
+	StartTime   float64     `json:"start_time" binding:"gte=0"`
+	EndTime     float64     `json:"end_time" binding:"gte=0"`
+	Confidence  float64     `json:"confidence" binding:"gte=0,lte=1"`
+	Metadata    common.JSON `json:"metadata"`
+}
+
+type IngestAppearancesRequest struct {
+	// FPS is used to validate frame numbers; falls back to metadata.fps of the video.
+	FPS         float64               `json:"fps" binding:"gte=0"`
+	Appearances []AppearanceDetection `json:"appearances" binding:"dive"`
+}
+
+type IngestAppearancesResponse struct {
+	VideoID         uuid.UUID `json:"video_id"`
+	AppearanceCount int       `json:"appearance_count"`
+	// ReviewedCount is the number of confirmed and rejected appearances kept;
+	// SkippedCount the number of detections dropped for overlapping one of them.
+	ReviewedCount  int    `json:"reviewed_count"`
+	SkippedCount   int    `json:"skipped_count"`
+	CharacterCount int    `json:"character_count"`
+	Status         string `json:"status"`
+}
+
 type CharacterAppearanceFilterAndPagination struct {
 	VideoID           uuid.UUID          `json:"video_id" form:"video_id"`
 	CharacterID       uuid.UUID          `json:"character_id" form:"character_id"`
@@ -46,9 +79,12 @@ type CharacterAppearanceFilterAndPagination struct {
 
 type VideoCharacterFilterAndPagination struct {
 	models.BaseRequestParamsUri
-	CharacterName  string  `json:"character_name" form:"character_name"`
-	MinConfidence  float64 `json:"min_confidence" form:"min_confidence"`
-	MinAppearances int     `json:"min_appearances" form:"min_appearances"`
+	// CharacterName matches characters whose name contains it, case-insensitively.
+	CharacterName string `json:"character_name" form:"character_name"`
+	// MinConfidence drops the appearances detected with a lower confidence
+	// before they are aggregated.
+	MinConfidence  float64 `json:"min_confidence" form:"min_confidence" binding:"gte=0,lte=1"`
+	MinAppearances int     `json:"min_appearances" form:"min_appearances" binding:"gte=0"`
 	Sort           string  `json:"sort" form:"sort"` // appearance_count.desc, total_duration.desc, first_appearance.asc, etc.
 }
 
@@ -59,16 +95,64 @@ type VideoSceneFilterAndPagination struct {
 	ExcludeCharactersStr []string    `form:"exclude_characters"`
 	IncludeCharacters    []uuid.UUID `json:"-"` // Hidden from JSON
 	ExcludeCharacters    []uuid.UUID `json:"-"` // Hidden from JSON
+	MinDuration          float64     `json:"min_duration" form:"min_duration" binding:"gte=0"`
+	MaxDuration          float64     `json:"max_duration" form:"max_duration" binding:"gte=0"` // 0 means no maximum
+	MinConfidence        float64     `json:"min_confidence" form:"min_confidence" binding:"gte=0,lte=1"`
+	// OverlapThreshold is the longest absence, in seconds, bridged when
+	// appearances are merged into scenes; defaults to DefaultSceneOverlapThreshold.
+	OverlapThreshold *float64 `json:"overlap_threshold" form:"overlap_threshold" binding:"omitempty,gte=0"`
+}
+
+const DefaultSceneOverlapThreshold = 1.0
+
+// GapTolerance returns the overlap threshold of the request or its default.
+func (f *VideoSceneFilterAndPagination) GapTolerance() float64 {
+	if f.OverlapThreshold == nil {
+		return DefaultSceneOverlapThreshold
+	}
+	return *f.OverlapThreshold
+}
+
+// SceneSegmentFilter tunes how appearances are turned into scenes: detections
+// below MinConfidence are ignored, absences of at most GapTolerance seconds are
+// bridged and scenes shorter than MinDuration or, when set, longer than
+// MaxDuration are dropped.
+type SceneSegmentFilter struct {
+	MinConfidence float64
+	GapTolerance  float64
+	MinDuration   float64
+	MaxDuration   float64
 }
 
+// VideoCharacterSummary aggregates the appearances of a character in a video.
+// TotalDuration is the time the character is on screen, overlapping
+// appearances counted once, and ScreenTimeShare its share of the video's
+// runtime, null while the duration of the video is unknown.
 type VideoCharacterSummary struct {
-	VideoID         uuid.UUID `json:"video_id"`
-	CharacterID     uuid.UUID `json:"character_id"`
-	CharacterName   string    `json:"character_name"`
-	CharacterAvatar string    `json:"character_avatar"`
-	DisplayName     string    `json:"display_name"`
-	StartTime       float64   `json:"start_time"`
-	EndTime         float64   `json:"end_time"`
+	VideoID           uuid.UUID `json:"video_id"`
+	CharacterID       uuid.UUID `json:"character_id"`
+	CharacterName     string    `json:"character_name"`
+	CharacterAvatar   string    `json:"character_avatar"`
+	AppearanceCount   int       `json:"appearance_count"`
+	TotalDuration     float64   `json:"total_duration"`
+	FirstAppearance   float64   `json:"first_appearance"`
+	LastAppearance    float64   `json:"last_appearance"`
+	AverageConfidence float64   `json:"average_confidence"`
+	MaxConfidence     float64   `json:"max_confidence"`
+	ScreenTimeShare   *float64  `json:"screen_time_share"`
+}
+
+func (VideoCharacterSummary) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"appearance_count":  {Column: "appearance_count"},
+		"total_duration":    {Column: "total_duration"},
+		"first_appearance":  {Column: "first_appearance"},
+		"last_appearance":   {Column: "last_appearance"},
+		"character_name":    {Column: "character_name"},
+		"confidence":        {Column: "average_confidence"},
+		"max_confidence":    {Column: "max_confidence"},
+		"screen_time_share": {Column: "screen_time_share"},
+	}
 }
 
 type VideoCharacterListResponse struct {
@@ -107,20 +191,14 @@ type VideoSceneListResponse struct {
 	Items []VideoScene `json:"items"`
 }
 
-// TimeSegmentResult represents a time segment with character information from raw SQL query
-type TimeSegmentResult struct {
-	StartTime       float64              `json:"start_time"`
-	EndTime         float64              `json:"end_time"`
-	Duration        float64              `json:"duration"`
-	TotalCharacters int                  `json:"total_characters"`
-	Characters      []CharacterInSegment `json:"characters"`
-}
-
-// CharacterInSegment represents character info within a time segment from raw SQL query
-type CharacterInSegment struct {
+// SceneAppearance is an appearance as read by the scene engine, with the
+// character it belongs to.
+type SceneAppearance struct {
 	CharacterID     uuid.UUID `json:"character_id"`
 	CharacterName   string    `json:"character_name"`
 	CharacterAvatar string    `json:"character_avatar"`
+	StartTime       float64   `json:"start_time"`
+	EndTime         float64   `json:"end_time"`
 	Confidence      float64   `json:"confidence"`
 }
 
@@ -135,6 +213,8 @@ type SceneSegment struct {
 	EndFrame       int         `json:"end_frame"`
 }
 
+// VideoCharacterSummaryFilter is VideoCharacterFilterAndPagination as passed
+// to the repository.
 type VideoCharacterSummaryFilter struct {
 	CharacterName  string  `json:"character_name"`
 	MinConfidence  float64 `json:"min_confidence"`
@@ -0,0 +1,68 @@
+package character
+
+import (
+	"smart-scene-app-api/common"
+	models "smart-scene-app-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Review statuses of an appearance. Pending appearances are replaced by the
+// next ingestion; confirmed and rejected ones survive it, and rejected ones are
+// hidden everywhere else.
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusConfirmed = "confirmed"
+	ReviewStatusRejected  = "rejected"
+)
+
+// CreateAppearanceRequest adds an appearance by hand. Frames are derived from
+// the times using FPS, or metadata.fps of the video.
+type CreateAppearanceRequest struct {
+	CharacterID uuid.UUID   `json:"character_id" binding:"required"`
+	StartTime   float64     `json:"start_time" binding:"gte=0"`
+	EndTime     float64     `json:"end_time" binding:"gte=0"`
+	Confidence  *float64    `json:"confidence" binding:"omitempty,gte=0,lte=1"`
+	FPS         float64     `json:"fps" binding:"gte=0"`
+	Metadata    common.JSON `json:"metadata"`
+}
+
+// UpdateAppearanceRequest trims or extends an appearance; omitted fields keep
+// their value.
+type UpdateAppearanceRequest struct {
+	CharacterID *uuid.UUID  `json:"character_id"`
+	StartTime   *float64    `json:"start_time" binding:"omitempty,gte=0"`
+	EndTime     *float64    `json:"end_time" binding:"omitempty,gte=0"`
+	Confidence  *float64    `json:"confidence" binding:"omitempty,gte=0,lte=1"`
+	FPS         float64     `json:"fps" binding:"gte=0"`
+	Metadata    common.JSON `json:"metadata"`
+}
+
+// SplitAppearanceRequest cuts an appearance in two at a time strictly inside it.
+type SplitAppearanceRequest struct {
+	At  float64 `json:"at" binding:"gt=0"`
+	FPS float64 `json:"fps" binding:"gte=0"`
+}
+
+// MergeAppearancesRequest joins appearances of the same character into one
+// spanning all of them.
+type MergeAppearancesRequest struct {
+	AppearanceIDs []uuid.UUID `json:"appearance_ids" binding:"required,min=2"`
+}
+
+type ReviewAppearanceRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending confirmed rejected"`
+}
+
+// VideoAppearanceFilterAndPagination lists the appearances of a video for
+// review, rejected ones included, ordered by start time.
+type VideoAppearanceFilterAndPagination struct {
+	models.BaseRequestParamsUri
+	CharacterID  string `json:"character_id" form:"character_id"`
+	ReviewStatus string `json:"review_status" form:"review_status" binding:"omitempty,oneof=pending confirmed rejected"`
+}
+
+type AppearanceListResponse struct {
+	models.BaseListResponse
+	Items []CharacterAppearance `json:"items"`
+}
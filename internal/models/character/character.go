@@ -19,15 +19,91 @@ type Character struct {
 	Avatar      string      `json:"avatar" gorm:"type:text"`
 	Metadata    common.JSON `json:"metadata" gorm:"type:jsonb"`
 	IsActive    bool        `json:"is_active" gorm:"default:true"`
+	Version     int         `json:"version" gorm:"not null;default:1"`
 }
 
 func (c *Character) TableName() string {
 	return common.POSTGRES_TABLE_NAME_CHARACTERS
 }
 
+func (c *Character) GetVersion() int {
+	return c.Version
+}
+
+func (c *Character) SetVersion(version int) {
+	c.Version = version
+}
+
+func (Character) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"name":       {Column: "name"},
+		"created_at": {Column: "created_at"},
+		"updated_at": {Column: "updated_at"},
+	}
+}
+
 type CharacterFilterAndPagination struct {
-	Name        string             `json:"name" form:"name"`
-	IsActive    *bool              `json:"is_active" form:"is_active"`
-	CreatedBy   uuid.UUID          `json:"created_by" form:"created_by"`
-	QueryParams models.QueryParams `json:"query_params" form:"query_params"`
+	models.BaseRequestParamsUri
+	// Name matches characters whose name contains it, case-insensitively.
+	Name      string    `json:"name" form:"name"`
+	IsActive  *bool     `json:"is_active" form:"is_active"`
+	CreatedBy uuid.UUID `json:"created_by" form:"created_by"`
+}
+
+type CreateCharacterRequest struct {
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description"`
+	Avatar      string      `json:"avatar"`
+	Metadata    common.JSON `json:"metadata"`
+	// IsActive defaults to true.
+	IsActive *bool `json:"is_active"`
+}
+
+// UpdateCharacterRequest changes the fields that are set; metadata replaces
+// the current value.
+type UpdateCharacterRequest struct {
+	Name        *string     `json:"name"`
+	Description *string     `json:"description"`
+	Avatar      *string     `json:"avatar"`
+	Metadata    common.JSON `json:"metadata"`
+	IsActive    *bool       `json:"is_active"`
+}
+
+// CharacterUsage counts the appearances of a character in the videos visible
+// to the viewer.
+type CharacterUsage struct {
+	VideoCount      int `json:"video_count"`
+	AppearanceCount int `json:"appearance_count"`
+}
+
+type CharacterResponse struct {
+	Character
+	CharacterUsage
+}
+
+type CharacterListResponse struct {
+	models.BaseListResponse
+	Items []CharacterResponse `json:"items"`
+}
+
+type CharacterVideoFilterAndPagination struct {
+	models.BaseRequestParamsUri
+}
+
+// CharacterVideoUsage is a video a character appears in, limited to the
+// videos visible to the caller.
+type CharacterVideoUsage struct {
+	VideoID         uuid.UUID `json:"video_id"`
+	Title           string    `json:"title"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	Status          string    `json:"status"`
+	AppearanceCount int       `json:"appearance_count"`
+	TotalDuration   float64   `json:"total_duration"`
+	FirstAppearance float64   `json:"first_appearance"`
+	LastAppearance  float64   `json:"last_appearance"`
+}
+
+type CharacterVideoListResponse struct {
+	models.BaseListResponse
+	Items []CharacterVideoUsage `json:"items"`
 }
@@ -0,0 +1,81 @@
+package character
+
+import (
+	videoModel "smart-scene-app-api/internal/models/video"
+
+	"github.com/google/uuid"
+)
+
+// Output formats of a co-occurrence matrix.
+const (
+	CoOccurrenceFormatJSON    = "json"
+	CoOccurrenceFormatGraphML = "graphml"
+	CoOccurrenceFormatGEXF    = "gexf"
+)
+
+// CoOccurrenceParams tunes how a co-occurrence matrix is computed. Detections
+// below MinConfidence are ignored and, when shared scenes are counted,
+// absences of at most OverlapThreshold seconds are bridged as in the scene
+// listing. Pairs sharing less than MinSharedSeconds are left out.
+type CoOccurrenceParams struct {
+	MinConfidence    float64  `json:"min_confidence" form:"min_confidence" binding:"gte=0,lte=1"`
+	OverlapThreshold *float64 `json:"overlap_threshold" form:"overlap_threshold" binding:"omitempty,gte=0"`
+	MinSharedSeconds float64  `json:"min_shared_seconds" form:"min_shared_seconds" binding:"gte=0"`
+	Format           string   `json:"format" form:"format" binding:"omitempty,oneof=json graphml gexf"`
+}
+
+// GapTolerance returns the overlap threshold of the params or its default.
+func (p *CoOccurrenceParams) GapTolerance() float64 {
+	if p.OverlapThreshold == nil {
+		return DefaultSceneOverlapThreshold
+	}
+	return *p.OverlapThreshold
+}
+
+// MaxCoOccurrenceVideos is the largest number of videos a co-occurrence matrix
+// is aggregated over.
+const MaxCoOccurrenceVideos = 500
+
+// CoOccurrenceFilter selects the videos a co-occurrence matrix is aggregated
+// over, among those visible to the viewer: the given videos, if any, matching
+// the tag filter of the video listing, if any. At least one of them must
+// select videos.
+type CoOccurrenceFilter struct {
+	CoOccurrenceParams
+	videoModel.VideoTagFilter
+	VideoIDsStr []string    `form:"video_ids"`
+	VideoIDs    []uuid.UUID `json:"-"`
+}
+
+// CoOccurrenceCharacter is a node of a co-occurrence matrix. ScreenTime is the
+// time the character is on screen, overlapping appearances counted once.
+type CoOccurrenceCharacter struct {
+	CharacterID     uuid.UUID `json:"character_id"`
+	CharacterName   string    `json:"character_name"`
+	CharacterAvatar string    `json:"character_avatar"`
+	ScreenTime      float64   `json:"screen_time"`
+	VideoCount      int       `json:"video_count"`
+}
+
+// CharacterCoOccurrence is a cell of a co-occurrence matrix: how long two
+// characters are on screen together, in how many scenes, and the Jaccard
+// index of their screen times, shared seconds over the seconds either is on
+// screen. The pair is unordered; CharacterID sorts before OtherCharacterID.
+type CharacterCoOccurrence struct {
+	CharacterID      uuid.UUID `json:"character_id"`
+	OtherCharacterID uuid.UUID `json:"other_character_id"`
+	SharedSeconds    float64   `json:"shared_seconds"`
+	SharedScenes     int       `json:"shared_scenes"`
+	Jaccard          float64   `json:"jaccard"`
+	VideoCount       int       `json:"video_count"`
+}
+
+// CoOccurrenceMatrix is the sparse co-occurrence matrix of the characters of
+// one or more videos: pairs sharing neither screen time nor a scene are left
+// out. Characters are sorted by screen time and pairs by shared seconds, both
+// descending.
+type CoOccurrenceMatrix struct {
+	VideoCount int                     `json:"video_count"`
+	Characters []CoOccurrenceCharacter `json:"characters"`
+	Pairs      []CharacterCoOccurrence `json:"pairs"`
+}
@@ -0,0 +1,81 @@
+package character
+
+import (
+	"smart-scene-app-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CharacterMerge records a merge of characters into a target so that it can
+// be undone.
+type CharacterMerge struct {
+	ID        uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CreatedAt time.Time              `json:"created_at" gorm:"type:timestamp;not null;default:now()"`
+	CreatedBy uuid.UUID              `json:"created_by" gorm:"type:uuid;not null"`
+	TargetID  uuid.UUID              `json:"target_id" gorm:"type:uuid;not null;index"`
+	Snapshot  CharacterMergeSnapshot `json:"snapshot" gorm:"type:jsonb;serializer:json"`
+	UndoneAt  *time.Time             `json:"undone_at,omitempty"`
+	UndoneBy  *uuid.UUID             `json:"undone_by,omitempty" gorm:"type:uuid"`
+}
+
+func (CharacterMerge) TableName() string {
+	return common.POSTGRES_TABLE_NAME_CHARACTER_MERGES
+}
+
+// CharacterMergeSnapshot holds what a merge changed: the characters as they
+// were before it and the rows it moved to the target or dropped.
+type CharacterMergeSnapshot struct {
+	Target         Character   `json:"target"`
+	Sources        []Character `json:"sources"`
+	SourcesDeleted bool        `json:"sources_deleted"`
+	// Appearances and VideoTags map a source ID to the IDs of the rows moved
+	// from it to the target.
+	Appearances map[uuid.UUID][]uuid.UUID `json:"appearances"`
+	VideoTags   map[uuid.UUID][]int       `json:"video_tags"`
+	// DroppedVideoTags were deleted because the target already had the same
+	// tag on the same video.
+	DroppedVideoTags []MergedVideoTag `json:"dropped_video_tags"`
+	VideoIDs         []uuid.UUID      `json:"video_ids"`
+}
+
+// MergedVideoTag is a video_tags row attached to a character.
+type MergedVideoTag struct {
+	ID          int       `json:"id"`
+	VideoID     uuid.UUID `json:"video_id"`
+	TagID       int       `json:"tag_id"`
+	CharacterID uuid.UUID `json:"character_id"`
+}
+
+// MergedAppearance identifies an appearance moved by a merge.
+type MergedAppearance struct {
+	ID          uuid.UUID
+	CharacterID uuid.UUID
+	VideoID     uuid.UUID
+}
+
+type MergeCharactersRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+	// DeleteSources deletes the merged characters instead of deactivating
+	// them.
+	DeleteSources bool `json:"delete_sources"`
+	// DryRun reports the rows the merge would change without changing them.
+	DryRun bool `json:"dry_run"`
+}
+
+type CharacterMergeResponse struct {
+	// MergeID identifies the merge for undoing it; it is not set on a dry run.
+	MergeID   *uuid.UUID  `json:"merge_id,omitempty"`
+	DryRun    bool        `json:"dry_run"`
+	TargetID  uuid.UUID   `json:"target_id"`
+	SourceIDs []uuid.UUID `json:"source_ids"`
+	// AppearancesMoved and VideoTagsMoved count the rows reassigned to the
+	// target; VideoTagsDropped the duplicates of tags the target already had.
+	AppearancesMoved int         `json:"appearances_moved"`
+	VideoTagsMoved   int         `json:"video_tags_moved"`
+	VideoTagsDropped int         `json:"video_tags_dropped"`
+	AffectedVideos   int         `json:"affected_videos"`
+	SourcesDeleted   bool        `json:"sources_deleted"`
+	Metadata         common.JSON `json:"metadata"`
+	UndoneAt         *time.Time  `json:"undone_at,omitempty"`
+}
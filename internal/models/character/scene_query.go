@@ -0,0 +1,49 @@
+package character
+
+import "github.com/google/uuid"
+
+// Operators of a SceneExpression.
+const (
+	SceneOpCharacter = "character"
+	SceneOpAnd       = "and"
+	SceneOpOr        = "or"
+	SceneOpNot       = "not"
+	SceneOpAtLeast   = "at_least"
+	SceneOpCast      = "cast"
+)
+
+// SceneExpression is a boolean expression over the characters on screen,
+// evaluated at every instant of a video. Examples:
+//
+//	{"op": "character", "name": "Alice"}
+//	{"op": "and", "args": [A, B]}, {"op": "or", "args": [A, B]}, {"op": "not", "args": [A]}
+//	{"op": "at_least", "n": 2, "args": [A, B, C]}
+//	{"op": "cast", "args": [A, B]}: exactly these characters and nobody else
+//
+// A character is referenced by character_id or by name, matched
+// case-insensitively. Operands of cast must be characters.
+type SceneExpression struct {
+	Op          string            `json:"op" binding:"required,oneof=character and or not at_least cast"`
+	CharacterID *uuid.UUID        `json:"character_id,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	N           int               `json:"n,omitempty"`
+	Args        []SceneExpression `json:"args,omitempty" binding:"dive"`
+}
+
+// SceneQueryRequest selects the scenes of a video where Expression holds. The
+// thresholds work as in VideoSceneFilterAndPagination.
+type SceneQueryRequest struct {
+	Expression       SceneExpression `json:"expression"`
+	MinDuration      float64         `json:"min_duration" binding:"gte=0"`
+	MaxDuration      float64         `json:"max_duration" binding:"gte=0"`
+	MinConfidence    float64         `json:"min_confidence" binding:"gte=0,lte=1"`
+	OverlapThreshold *float64        `json:"overlap_threshold" binding:"omitempty,gte=0"`
+}
+
+// GapTolerance returns the overlap threshold of the request or its default.
+func (r *SceneQueryRequest) GapTolerance() float64 {
+	if r.OverlapThreshold == nil {
+		return DefaultSceneOverlapThreshold
+	}
+	return *r.OverlapThreshold
+}
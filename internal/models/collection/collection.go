@@ -0,0 +1,201 @@
+package collection
+
+import (
+	"smart-scene-app-api/common"
+	models "smart-scene-app-api/internal/models"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ItemWarningVideoDeleted is set on items whose video was trashed or purged.
+	ItemWarningVideoDeleted = "video_deleted"
+	// ItemWarningVideoUnavailable is set on items whose video the caller can no
+	// longer see.
+	ItemWarningVideoUnavailable = "video_unavailable"
+)
+
+// Collection is a user-owned, ordered list of videos and scenes. It has the
+// same visibility levels and shares as videos.
+type Collection struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:uuid;not null"`
+	UpdatedBy   uuid.UUID `json:"updated_by" gorm:"type:uuid;not null"`
+	Name        string    `json:"name" gorm:"type:text;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	Visibility  string    `json:"visibility" gorm:"type:text;not null;default:'private'"`
+}
+
+func (Collection) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COLLECTIONS
+}
+
+func (Collection) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"created_at": {Column: "created_at", Directions: models.SortBoth},
+		"updated_at": {Column: "updated_at", Directions: models.SortBoth},
+		"name":       {Column: "name", Directions: models.SortBoth},
+	}
+}
+
+// AccessFor returns the access level of viewer on the collection given the
+// shares that apply to the viewer.
+func (c *Collection) AccessFor(viewer common.Viewer, grants []CollectionShare) videoModel.AccessLevel {
+	var permissions []string
+	for _, grant := range grants {
+		if grant.AppliesTo(viewer) {
+			permissions = append(permissions, grant.Permission)
+		}
+	}
+	return videoModel.ResolveAccess(c.CreatedBy, c.Visibility, viewer, permissions)
+}
+
+// CollectionItem is a whole video, or a scene of it when StartTime and
+// EndTime are set. Positions are contiguous from 0. Items outlive their video
+// so that owners can see what was removed.
+type CollectionItem struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CreatedBy    uuid.UUID `json:"created_by" gorm:"type:uuid;not null"`
+	CollectionID uuid.UUID `json:"collection_id" gorm:"type:uuid;not null;index"`
+	Position     int       `json:"position" gorm:"not null"`
+	VideoID      uuid.UUID `json:"video_id" gorm:"type:uuid;not null;index"`
+	StartTime    *float64  `json:"start_time,omitempty" gorm:"type:decimal(10,3)"`
+	EndTime      *float64  `json:"end_time,omitempty" gorm:"type:decimal(10,3)"`
+	Note         string    `json:"note" gorm:"type:text"`
+}
+
+func (CollectionItem) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COLLECTION_ITEMS
+}
+
+// CollectionShare grants a user or every user of a role access to a
+// collection.
+type CollectionShare struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CollectionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"collection_id"`
+	UserID       *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	RoleID       *uuid.UUID `gorm:"type:uuid" json:"role_id,omitempty"`
+	Permission   string     `gorm:"type:text;not null" json:"permission"`
+	CreatedBy    uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (CollectionShare) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COLLECTION_SHARES
+}
+
+func (s CollectionShare) AppliesTo(viewer common.Viewer) bool {
+	if s.UserID != nil && *s.UserID == viewer.UserID {
+		return true
+	}
+	return s.RoleID != nil && viewer.RoleID != uuid.Nil && *s.RoleID == viewer.RoleID
+}
+
+type CollectionFilterAndPagination struct {
+	models.BaseRequestParamsUri
+	Name string `form:"name"`
+	// Owned limits the listing to the caller's own collections.
+	Owned bool `form:"owned"`
+}
+
+// CollectionItemInput references a whole video, or a scene of it with both
+// start_time and end_time, as returned by the scenes endpoint.
+type CollectionItemInput struct {
+	VideoID   uuid.UUID `json:"video_id" binding:"required"`
+	StartTime *float64  `json:"start_time" binding:"omitempty,gte=0"`
+	EndTime   *float64  `json:"end_time" binding:"omitempty,gte=0"`
+	Note      string    `json:"note"`
+}
+
+type CreateCollectionRequest struct {
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	Visibility  string                `json:"visibility" binding:"omitempty,oneof=private shared organization"`
+	Items       []CollectionItemInput `json:"items" binding:"dive"`
+}
+
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1"`
+	Description *string `json:"description"`
+}
+
+// AddCollectionItemsRequest inserts items at Position, or at the end when it
+// is not given.
+type AddCollectionItemsRequest struct {
+	Items    []CollectionItemInput `json:"items" binding:"required,min=1,dive"`
+	Position *int                  `json:"position" binding:"omitempty,gte=0"`
+}
+
+type UpdateCollectionItemRequest struct {
+	StartTime *float64 `json:"start_time" binding:"omitempty,gte=0"`
+	EndTime   *float64 `json:"end_time" binding:"omitempty,gte=0"`
+	Note      *string  `json:"note"`
+	// WholeVideo clears the scene range.
+	WholeVideo bool `json:"whole_video"`
+}
+
+// ReorderCollectionItemsRequest lists every item of the collection in the new
+// order.
+type ReorderCollectionItemsRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required,min=1"`
+}
+
+type DuplicateCollectionRequest struct {
+	// Name defaults to "<name> (copy)".
+	Name string `json:"name"`
+}
+
+type CollectionResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Visibility  string                   `json:"visibility"`
+	CreatedBy   uuid.UUID                `json:"created_by"`
+	UpdatedBy   uuid.UUID                `json:"updated_by"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	ItemCount   int                      `json:"item_count"`
+	Items       []CollectionItemResponse `json:"items,omitempty"`
+}
+
+type CollectionItemResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Position  int       `json:"position"`
+	VideoID   uuid.UUID `json:"video_id"`
+	StartTime *float64  `json:"start_time,omitempty"`
+	EndTime   *float64  `json:"end_time,omitempty"`
+	Note      string    `json:"note"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// Video is empty when the item is stale.
+	Video *CollectionItemVideo `json:"video,omitempty"`
+	// Stale items reference a video that was deleted or is no longer visible
+	// to the caller; Warning tells which.
+	Stale   bool   `json:"stale"`
+	Warning string `json:"warning,omitempty"`
+}
+
+type CollectionItemVideo struct {
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Duration     int    `json:"duration"`
+	Status       string `json:"status"`
+}
+
+type CollectionListResponse struct {
+	models.BaseListResponse
+	Items []CollectionResponse `json:"items"`
+}
+
+type CollectionSharingResponse struct {
+	CollectionID uuid.UUID         `json:"collection_id"`
+	Visibility   string            `json:"visibility"`
+	Shares       []CollectionShare `json:"shares"`
+}
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"fmt"
+	"smart-scene-app-api/common"
+
+	"github.com/google/uuid"
+)
+
+type VideoAnalysisPayload struct {
+	VideoID uuid.UUID `json:"video_id"`
+}
+
+// VideoAnalysisJobID is the queue job ID of a video's analysis; using the video
+// ID keeps a video from being queued twice.
+func VideoAnalysisJobID(videoID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", common.JOB_TYPE_VIDEO_ANALYSIS, videoID)
+}
+
+// DetectorRequest is posted to the configured detector to start an analysis.
+// The detector reports back through the webhook endpoint.
+type DetectorRequest struct {
+	JobID    string    `json:"job_id"`
+	VideoID  uuid.UUID `json:"video_id"`
+	VideoURL string    `json:"video_url"`
+	Duration int       `json:"duration"`
+	Attempt  int       `json:"attempt"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"smart-scene-app-api/common"
+	"sort"
+	"strings"
+)
+
+type SortDirections int
+
+const (
+	SortAsc SortDirections = 1 << iota
+	SortDesc
+	SortBoth = SortAsc | SortDesc
+)
+
+const (
+	NullsFirst = "first"
+	NullsLast  = "last"
+)
+
+// SortColumn declares a field a listing can be sorted by.
+type SortColumn struct {
+	// Column is the column of the model's table.
+	Column string
+	// Directions allowed for the field; zero allows both.
+	Directions SortDirections
+}
+
+// Sortable is implemented by models whose listings accept a sort. The keys of
+// SortColumns are the field names used in the query string. Listings of
+// models that do not implement it cannot be sorted by the client.
+type Sortable interface {
+	SortColumns() map[string]SortColumn
+}
+
+// SortField is a parsed, whitelisted element of a sort.
+type SortField struct {
+	Name   string
+	Column string
+	Desc   bool
+	// Nulls is NullsFirst, NullsLast or empty for the database default.
+	Nulls string
+}
+
+// Parse checks the sort against the allowed columns. Each element is written
+// field[.asc|.desc][.nulls_first|.nulls_last]; the direction defaults to asc.
+func (s QuerySort) Parse(columns map[string]SortColumn) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s.Origin, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		tokens := strings.Split(strings.ToLower(part), ".")
+		name := tokens[0]
+		column, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q, allowed: %s", common.ErrInvalidSort, name, allowedSortFields(columns))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: field %q is given twice", common.ErrInvalidSort, name)
+		}
+		seen[name] = true
+
+		field := SortField{Name: name, Column: column.Column}
+		for _, token := range tokens[1:] {
+			switch token {
+			case "asc":
+				field.Desc = false
+			case "desc":
+				field.Desc = true
+			case "nulls_first", "nullsfirst":
+				field.Nulls = NullsFirst
+			case "nulls_last", "nullslast":
+				field.Nulls = NullsLast
+			default:
+				return nil, fmt.Errorf("%w: unknown modifier %q in %q, expected asc, desc, nulls_first or nulls_last", common.ErrInvalidSort, token, part)
+			}
+		}
+
+		directions := column.Directions
+		if directions == 0 {
+			directions = SortBoth
+		}
+		if (field.Desc && directions&SortDesc == 0) || (!field.Desc && directions&SortAsc == 0) {
+			return nil, fmt.Errorf("%w: field %q cannot be sorted %s", common.ErrInvalidSort, name, directionName(field.Desc))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func allowedSortFields(columns map[string]SortColumn) string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func directionName(desc bool) string {
+	if desc {
+		return "desc"
+	}
+	return "asc"
+}
@@ -42,19 +42,23 @@ func (TagCategory) TableName() string {
 }
 
 type Tag struct {
-	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	CategoryID  int       `gorm:"not null;index" json:"category_id"`
-	Name        string    `gorm:"type:text;not null" json:"name"`
-	Code        string    `gorm:"type:text;not null" json:"code"`
-	Description string    `gorm:"type:text" json:"description"`
-	Color       string    `gorm:"type:text" json:"color"`
-	Icon        string    `gorm:"type:text" json:"icon"`
-	SortOrder   int       `gorm:"default:0" json:"sort_order"`
-	UsageCount  int       `gorm:"default:0" json:"usage_count"`
-	IsActive    bool      `gorm:"default:true" json:"is_active"`
-	IsSystemTag bool      `gorm:"default:false" json:"is_system_tag"`
-	CreatedBy   uuid.UUID `gorm:"type:uuid" json:"created_by"`
-	UpdatedBy   uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID  int    `gorm:"not null;index" json:"category_id"`
+	Name        string `gorm:"type:text;not null" json:"name"`
+	Code        string `gorm:"type:text;not null" json:"code"`
+	Description string `gorm:"type:text" json:"description"`
+	Color       string `gorm:"type:text" json:"color"`
+	Icon        string `gorm:"type:text" json:"icon"`
+	SortOrder   int    `gorm:"default:0" json:"sort_order"`
+	UsageCount  int    `gorm:"default:0" json:"usage_count"`
+	IsActive    bool   `gorm:"default:true" json:"is_active"`
+	IsSystemTag bool   `gorm:"default:false" json:"is_system_tag"`
+	// RangeMin and RangeMax bound the values a tag of a range category stands
+	// for; nil leaves that side open.
+	RangeMin  *float64  `gorm:"type:numeric" json:"range_min,omitempty"`
+	RangeMax  *float64  `gorm:"type:numeric" json:"range_max,omitempty"`
+	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	UpdatedBy uuid.UUID `gorm:"type:uuid" json:"updated_by"`
 	models.Base
 
 	Category TagCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
@@ -64,6 +68,15 @@ func (Tag) TableName() string {
 	return common.POSTGRES_TABLE_NAME_TAGS
 }
 
+func (Tag) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"sort_order":  {Column: "sort_order"},
+		"name":        {Column: "name"},
+		"usage_count": {Column: "usage_count"},
+		"created_at":  {Column: "created_at"},
+	}
+}
+
 type TagPositionCategory struct {
 	ID            int    `gorm:"primaryKey;autoIncrement" json:"id"`
 	TagPositionID int    `gorm:"not null;index" json:"tag_position_id"`
@@ -81,6 +94,12 @@ func (TagPositionCategory) TableName() string {
 	return common.POSTGRES_TABLE_NAME_TAG_POSITION_CATEGORIES
 }
 
+func (TagPositionCategory) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"sort_order": {Column: "sort_order"},
+	}
+}
+
 type TagFilterRequest struct {
 	models.BaseRequestParamsUri
 	PositionCode string `form:"position" json:"position"`
@@ -107,15 +126,60 @@ type TagCategoryResponse struct {
 }
 
 type TagResponse struct {
-	TagID      int    `json:"tag_id"`
-	TagName    string `json:"tag_name"`
-	TagCode    string `json:"tag_code"`
-	Color      string `json:"color"`
-	UsageCount int    `json:"usage_count"`
-	IsActive   bool   `json:"is_active"`
+	TagID      int      `json:"tag_id"`
+	TagName    string   `json:"tag_name"`
+	TagCode    string   `json:"tag_code"`
+	Color      string   `json:"color"`
+	UsageCount int      `json:"usage_count"`
+	IsActive   bool     `json:"is_active"`
+	RangeMin   *float64 `json:"range_min,omitempty"`
+	RangeMax   *float64 `json:"range_max,omitempty"`
 }
 
 type TagListResponse struct {
 	models.BaseListResponse
 	Items []TagHierarchyResponse `json:"items"`
 }
+
+const (
+	FilterTypeSingle   = "single"
+	FilterTypeMultiple = "multiple"
+	FilterTypeRange    = "range"
+)
+
+// VideoTag attaches a tag to a video, optionally scoped to a character of the
+// video.
+type VideoTag struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	VideoID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"video_id"`
+	TagID       int        `gorm:"not null;index" json:"tag_id"`
+	CharacterID *uuid.UUID `gorm:"type:uuid" json:"character_id"`
+
+	Tag Tag `gorm:"foreignKey:TagID" json:"tag,omitempty"`
+}
+
+func (VideoTag) TableName() string {
+	return common.POSTGRES_TABLE_NAME_VIDEO_TAGS
+}
+
+type VideoTagInput struct {
+	TagID       int        `json:"tag_id" binding:"required"`
+	CharacterID *uuid.UUID `json:"character_id"`
+}
+
+type VideoTagsRequest struct {
+	Tags []VideoTagInput `json:"tags" binding:"dive"`
+}
+
+type VideoTagResponse struct {
+	ID           int        `json:"id"`
+	TagID        int        `json:"tag_id"`
+	TagName      string     `json:"tag_name"`
+	TagCode      string     `json:"tag_code"`
+	TagColor     string     `json:"tag_color"`
+	CategoryID   int        `json:"category_id"`
+	CategoryName string     `json:"category_name"`
+	CategoryCode string     `json:"category_code"`
+	FilterType   string     `json:"filter_type"`
+	CharacterID  *uuid.UUID `json:"character_id"`
+}
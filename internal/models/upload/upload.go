@@ -0,0 +1,98 @@
+package upload
+
+import (
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/config"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UploadStatusUploading  = "uploading"
+	UploadStatusAssembling = "assembling"
+	UploadStatusCompleted  = "completed"
+	UploadStatusAborted    = "aborted"
+)
+
+const (
+	DefaultChunkSize int64 = 8 << 20
+	MaxChunkSize     int64 = 64 << 20
+	MinChunkSize     int64 = 256 << 10
+	SessionLifetime        = 7 * 24 * time.Hour
+)
+
+type UploadSession struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CreatedBy   uuid.UUID   `json:"created_by" gorm:"type:uuid;not null"`
+	Status      string      `json:"status" gorm:"type:text;not null;default:'uploading'"`
+	Title       string      `json:"title" gorm:"type:text;not null"`
+	FileName    string      `json:"file_name" gorm:"type:text;not null"`
+	ContentType string      `json:"content_type" gorm:"type:text"`
+	TotalSize   int64       `json:"total_size" gorm:"not null"`
+	ChunkSize   int64       `json:"chunk_size" gorm:"not null"`
+	TotalChunks int         `json:"total_chunks" gorm:"not null"`
+	Duration    int         `json:"duration" gorm:"type:int;not null;default:0"`
+	Metadata    common.JSON `json:"metadata" gorm:"type:jsonb"`
+	VideoID     *uuid.UUID  `json:"video_id" gorm:"type:uuid"`
+	ExpiresAt   time.Time   `json:"expires_at" gorm:"not null"`
+}
+
+func (UploadSession) TableName() string {
+	return common.POSTGRES_TABLE_NAME_UPLOAD_SESSIONS
+}
+
+// PartKey is the storage key of an uploaded, not yet assembled chunk.
+func (u UploadSession) PartKey(partNumber int) string {
+	return "uploads/" + u.ID.String() + "/parts/" + config.GetPartKey(partNumber)
+}
+
+// StagingPartKey is where an attempt at uploading a chunk is written until its
+// size and checksum are checked, so that a bad re-send never touches the part
+// already accepted under PartKey.
+func (u UploadSession) StagingPartKey(partNumber int, attempt uuid.UUID) string {
+	return "uploads/" + u.ID.String() + "/staging/" + config.GetPartKey(partNumber) + "_" + attempt.String()
+}
+
+// ExpectedPartSize returns the size every chunk must have; only the last one may be shorter.
+func (u UploadSession) ExpectedPartSize(partNumber int) int64 {
+	if partNumber == u.TotalChunks {
+		return u.TotalSize - int64(u.TotalChunks-1)*u.ChunkSize
+	}
+	return u.ChunkSize
+}
+
+type UploadPart struct {
+	ID         int       `json:"-" gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time `json:"created_at"`
+	UploadID   uuid.UUID `json:"upload_id" gorm:"type:uuid;not null;uniqueIndex:idx_upload_parts_upload_part"`
+	PartNumber int       `json:"part_number" gorm:"not null;uniqueIndex:idx_upload_parts_upload_part"`
+	Size       int64     `json:"size" gorm:"not null"`
+	Checksum   string    `json:"checksum" gorm:"type:text;not null"`
+}
+
+func (UploadPart) TableName() string {
+	return common.POSTGRES_TABLE_NAME_UPLOAD_PARTS
+}
+
+type InitiateUploadRequest struct {
+	Title       string      `json:"title" binding:"required"`
+	FileName    string      `json:"file_name" binding:"required"`
+	ContentType string      `json:"content_type"`
+	TotalSize   int64       `json:"total_size" binding:"required,gt=0"`
+	ChunkSize   int64       `json:"chunk_size"`
+	Duration    int         `json:"duration" binding:"gte=0"`
+	Metadata    common.JSON `json:"metadata"`
+}
+
+type UploadStatusResponse struct {
+	UploadSession
+	// ReceivedOffset is the number of bytes received contiguously from the start of the file.
+	// A client resumes by uploading the part starting at this offset.
+	ReceivedOffset int64        `json:"received_offset"`
+	ReceivedBytes  int64        `json:"received_bytes"`
+	ReceivedParts  []UploadPart `json:"received_parts"`
+	MissingParts   []int        `json:"missing_parts"`
+}
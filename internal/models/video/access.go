@@ -0,0 +1,123 @@
+package video
+
+import (
+	"smart-scene-app-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// VisibilityPrivate videos are only accessible to their owner.
+	VisibilityPrivate = "private"
+	// VisibilityShared videos are accessible to the users and roles they are
+	// shared with.
+	VisibilityShared = "shared"
+	// VisibilityOrganization videos can be viewed by every user; shares grant
+	// editing.
+	VisibilityOrganization = "organization"
+)
+
+const (
+	SharePermissionViewer = "viewer"
+	SharePermissionEditor = "editor"
+)
+
+// AccessLevel is what a caller may do with a video. Levels are ordered: each
+// one allows everything the previous ones do.
+type AccessLevel int
+
+const (
+	AccessNone AccessLevel = iota
+	AccessViewer
+	AccessEditor
+	// AccessOwner allows deleting, restoring and managing sharing.
+	AccessOwner
+)
+
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityShared, VisibilityOrganization:
+		return true
+	}
+	return false
+}
+
+// VideoShare grants a user or every user of a role access to a video.
+type VideoShare struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	VideoID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"video_id"`
+	UserID     *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	RoleID     *uuid.UUID `gorm:"type:uuid" json:"role_id,omitempty"`
+	Permission string     `gorm:"type:text;not null" json:"permission"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (VideoShare) TableName() string {
+	return common.POSTGRES_TABLE_NAME_VIDEO_SHARES
+}
+
+// AccessFor returns the access level of viewer on the video given the shares
+// that apply to the viewer.
+func (v *Video) AccessFor(viewer common.Viewer, grants []VideoShare) AccessLevel {
+	var permissions []string
+	for _, grant := range grants {
+		if grant.AppliesTo(viewer) {
+			permissions = append(permissions, grant.Permission)
+		}
+	}
+	return ResolveAccess(v.CreatedBy, v.Visibility, viewer, permissions)
+}
+
+// ResolveAccess returns the access level of viewer on a resource owned by
+// ownerID with the given visibility. permissions are those of the shares of
+// the resource applying to the viewer.
+func ResolveAccess(ownerID uuid.UUID, visibility string, viewer common.Viewer, permissions []string) AccessLevel {
+	if viewer.Admin || ownerID == viewer.UserID {
+		return AccessOwner
+	}
+
+	level := AccessNone
+	if visibility == VisibilityOrganization {
+		level = AccessViewer
+	}
+	if visibility == VisibilityPrivate {
+		return level
+	}
+	for _, permission := range permissions {
+		switch {
+		case permission == SharePermissionEditor:
+			level = AccessEditor
+		case permission == SharePermissionViewer && level < AccessViewer:
+			level = AccessViewer
+		}
+	}
+	return level
+}
+
+func (s VideoShare) AppliesTo(viewer common.Viewer) bool {
+	if s.UserID != nil && *s.UserID == viewer.UserID {
+		return true
+	}
+	return s.RoleID != nil && viewer.RoleID != uuid.Nil && *s.RoleID == viewer.RoleID
+}
+
+type VideoVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=private shared organization"`
+}
+
+// VideoShareRequest shares a video with exactly one of a user or a role.
+// Sharing again with the same user or role replaces the permission.
+type VideoShareRequest struct {
+	UserID     *uuid.UUID `json:"user_id"`
+	RoleID     *uuid.UUID `json:"role_id"`
+	Permission string     `json:"permission" binding:"required,oneof=viewer editor"`
+}
+
+type VideoSharingResponse struct {
+	VideoID    uuid.UUID    `json:"video_id"`
+	Visibility string       `json:"visibility"`
+	Shares     []VideoShare `json:"shares"`
+}
@@ -8,19 +8,31 @@ import (
 )
 
 type VideoListingResponse struct {
-	ID               uuid.UUID      `json:"id"`
-	Title            string         `json:"title"`
-	ThumbnailURL     string         `json:"thumbnail_url"`
-	Duration         int            `json:"duration"`
-	CharacterCount   int            `json:"character_count"`
-	Status           string         `json:"status"`
-	CreatedAt        string         `json:"created_at"`
-	UpdatedAt        string         `json:"updated_at"`
-	FilePath         string         `json:"file_path"`
-	Tags             []VideoTagInfo `json:"tags"`
-	VisibleTagsCount int            `json:"visible_tags_count"`
-	TotalTagsCount   int            `json:"total_tags_count"`
-	Metadata         common.JSON    `json:"metadata"`
+	ID                 uuid.UUID      `json:"id"`
+	Title              string         `json:"title"`
+	ThumbnailURL       string         `json:"thumbnail_url"`
+	Duration           int            `json:"duration"`
+	CharacterCount     int            `json:"character_count"`
+	Status             string         `json:"status"`
+	CreatedAt          string         `json:"created_at"`
+	UpdatedAt          string         `json:"updated_at"`
+	FilePath           string         `json:"file_path"`
+	PlaybackURL        string         `json:"playback_url"`
+	ThumbnailSignedURL string         `json:"thumbnail_signed_url"`
+	Tags               []VideoTagInfo `json:"tags"`
+	VisibleTagsCount   int            `json:"visible_tags_count"`
+	TotalTagsCount     int            `json:"total_tags_count"`
+	Metadata           common.JSON    `json:"metadata"`
+	// Set when the listing is a full-text search.
+	Search *VideoSearchHit `json:"search,omitempty"`
+}
+
+// VideoSearchHit holds the rank of a video for a full-text query and the
+// matched text with terms wrapped in <mark> tags.
+type VideoSearchHit struct {
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
 }
 
 type VideoTagInfo struct {
@@ -37,3 +49,65 @@ type VideoListResponse struct {
 	models.BaseListResponse
 	Items []VideoListingResponse `json:"items"`
 }
+
+// VideoFacets counts the videos matching the current filters per tag, status
+// and character. Tag counts of a multi-select category ignore the filter on
+// that category, so that they show how many videos each alternative would add.
+type VideoFacets struct {
+	Tags       []TagCategoryFacet `json:"tags"`
+	Statuses   []StatusFacet      `json:"statuses"`
+	Characters []CharacterFacet   `json:"characters"`
+}
+
+type TagCategoryFacet struct {
+	CategoryID   int        `json:"category_id"`
+	CategoryName string     `json:"category_name"`
+	CategoryCode string     `json:"category_code"`
+	FilterType   string     `json:"filter_type"`
+	Tags         []TagFacet `json:"tags"`
+}
+
+type TagFacet struct {
+	TagID   int    `json:"tag_id"`
+	TagName string `json:"tag_name"`
+	TagCode string `json:"tag_code"`
+	Count   int64  `json:"count"`
+}
+
+type StatusFacet struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+type CharacterFacet struct {
+	CharacterID uuid.UUID `json:"character_id"`
+	Name        string    `json:"name"`
+	Count       int64     `json:"count"`
+}
+
+// TagFacetCount is a row of the per-tag facet query.
+type TagFacetCount struct {
+	TagID            int
+	TagName          string
+	TagCode          string
+	CategoryID       int
+	CategoryName     string
+	CategoryCode     string
+	CategoryPriority int
+	FilterType       string
+	Count            int64
+}
+
+// FilterTag is a tag selected in a listing filter with its category.
+type FilterTag struct {
+	TagID      int
+	CategoryID int
+	FilterType string
+}
+
+// FilterCategory is a tag category referenced by a listing filter.
+type FilterCategory struct {
+	ID         int
+	Code       string
+	FilterType string
+}
@@ -0,0 +1,92 @@
+package video
+
+import (
+	"smart-scene-app-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VideoStatusPending    = "pending"
+	VideoStatusProcessing = "processing"
+	VideoStatusCompleted  = "completed"
+	VideoStatusFailed     = "failed"
+)
+
+// VideoStatuses lists the statuses in lifecycle order.
+var VideoStatuses = []string{VideoStatusPending, VideoStatusProcessing, VideoStatusCompleted, VideoStatusFailed}
+
+const (
+	StatusActorUser   = "user"
+	StatusActorSystem = "system"
+)
+
+// videoStatusTransitions lists the statuses reachable from each status.
+// failed -> pending is the only way back and is used to retry an analysis.
+var videoStatusTransitions = map[string][]string{
+	VideoStatusPending:    {VideoStatusProcessing},
+	VideoStatusProcessing: {VideoStatusCompleted, VideoStatusFailed},
+	VideoStatusCompleted:  {},
+	VideoStatusFailed:     {VideoStatusPending},
+}
+
+func IsValidVideoStatus(status string) bool {
+	_, ok := videoStatusTransitions[status]
+	return ok
+}
+
+func CanTransitionVideoStatus(from, to string) bool {
+	return common.ContainsString(videoStatusTransitions[from], to)
+}
+
+// VideoStatusPath returns the statuses to go through, in order, to get from
+// "from" to "to" (excluding "from"). It is empty when both are equal and nil
+// when "to" cannot be reached.
+func VideoStatusPath(from, to string) []string {
+	if from == to {
+		return []string{}
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range videoStatusTransitions[current] {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+			if next == to {
+				var path []string
+				for step := to; step != from; step = previous[step] {
+					path = append([]string{step}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+type VideoStatusHistory struct {
+	ID         int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"type:timestamptz;not null;default:now()"`
+	VideoID    uuid.UUID  `json:"video_id" gorm:"type:uuid;not null;index"`
+	FromStatus string     `json:"from_status" gorm:"type:text;not null"`
+	ToStatus   string     `json:"to_status" gorm:"type:text;not null"`
+	Reason     string     `json:"reason" gorm:"type:text"`
+	ActorType  string     `json:"actor_type" gorm:"type:text;not null;default:'user'"`
+	ActorID    *uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+}
+
+func (VideoStatusHistory) TableName() string {
+	return common.POSTGRES_TABLE_NAME_VIDEO_STATUS_HISTORIES
+}
+
+type VideoStatusTransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Video struct {
@@ -22,17 +23,93 @@ type Video struct {
 	ThumbnailURL         string      `json:"thumbnail_url" gorm:"type:text"`
 	HasCharacterAnalysis bool        `json:"has_character_analysis" gorm:"default:false"`
 	CharacterCount       int         `json:"character_count" gorm:"type:int;default:0"`
+	// Visibility is private, shared or organization; see VideoShare.
+	Visibility string `json:"visibility" gorm:"type:text;not null;default:'private'"`
+	// Version is incremented on every change, including to the appearances
+	// and tags of the video; it is served as the ETag.
+	Version int `json:"version" gorm:"not null;default:1"`
+	// Trashed videos are hidden from regular queries until restored or purged.
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+	DeletedBy *uuid.UUID     `json:"deleted_by,omitempty" gorm:"type:uuid"`
+
+	PlaybackURL        string `json:"playback_url,omitempty" gorm:"-"`
+	ThumbnailSignedURL string `json:"thumbnail_signed_url,omitempty" gorm:"-"`
 }
 
 func (Video) TableName() string {
 	return common.POSTGRES_TABLE_NAME_VIDEOS
 }
 
+func (v *Video) GetVersion() int {
+	return v.Version
+}
+
+func (v *Video) SetVersion(version int) {
+	v.Version = version
+}
+
+func (Video) SortColumns() map[string]models.SortColumn {
+	return map[string]models.SortColumn{
+		"created_at":      {Column: "created_at"},
+		"updated_at":      {Column: "updated_at"},
+		"title":           {Column: "title"},
+		"duration":        {Column: "duration"},
+		"status":          {Column: "status"},
+		"character_count": {Column: "character_count"},
+	}
+}
+
 type VideoFilterAndPagination struct {
 	models.BaseRequestParamsUri
-	Title     string    `json:"title" form:"title"`
+	Title string `json:"title" form:"title"`
+	// Q is a full-text query over title, tags, characters and metadata, in
+	// web search syntax ("quoted phrases", or, -excluded).
+	Q         string    `json:"q" form:"q"`
 	Status    string    `json:"status" form:"status"`
 	CreatedBy uuid.UUID `json:"created_by" form:"created_by"`
-	TagIDs    []int     `json:"tag_ids" form:"tag_ids"`
-	TagCodes  []string  `json:"tag_codes" form:"tag_codes"`
+	VideoTagFilter
+	// Facets adds VideoFacets to the Extra field of the response.
+	Facets bool `json:"facets" form:"facets"`
+}
+
+// VideoTagFilter selects videos by their tags. Tags are ORed within a
+// category and ANDed across categories; tag codes may be comma separated.
+type VideoTagFilter struct {
+	TagIDs          []int    `json:"tag_ids" form:"tag_ids"`
+	TagCodes        []string `json:"tag_codes" form:"tag_codes"`
+	ExcludeTagIDs   []int    `json:"exclude_tag_ids" form:"exclude_tag_ids"`
+	ExcludeTagCodes []string `json:"exclude_tag_codes" form:"exclude_tag_codes"`
+	// TagRanges select the tags of a range category overlapping a range,
+	// written "category_code:min:max" with either bound optional.
+	TagRanges []string `json:"tag_ranges" form:"tag_ranges"`
+}
+
+// Selects reports whether the filter requires tags, as opposed to only
+// excluding some.
+func (f VideoTagFilter) Selects() bool {
+	return len(f.TagIDs) > 0 || len(f.TagCodes) > 0 || len(f.TagRanges) > 0
+}
+
+type VideoTrashFilterAndPagination struct {
+	models.BaseRequestParamsUri
+	Title     string    `json:"title" form:"title"`
+	DeletedBy uuid.UUID `json:"deleted_by" form:"deleted_by"`
+}
+
+type VideoTrashItem struct {
+	ID           uuid.UUID  `json:"id"`
+	Title        string     `json:"title"`
+	ThumbnailURL string     `json:"thumbnail_url"`
+	Duration     int        `json:"duration"`
+	Status       string     `json:"status"`
+	CreatedBy    uuid.UUID  `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DeletedAt    time.Time  `json:"deleted_at"`
+	DeletedBy    *uuid.UUID `json:"deleted_by"`
+	PurgeAt      time.Time  `json:"purge_at"`
+}
+
+type VideoTrashListResponse struct {
+	models.BaseListResponse
+	Items []VideoTrashItem `json:"items"`
 }
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Headers carrying the delivery signature. The signature is the hex encoded
+// HMAC-SHA256 of "<timestamp>.<nonce>.<raw body>" keyed with the integration
+// secret, optionally prefixed with "sha256=".
+const (
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderNonce     = "X-Webhook-Nonce"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	EventTypeProgress  = "progress"
+	EventTypeCompleted = "completed"
+	EventTypeFailed    = "failed"
+)
+
+const (
+	DeliveryProcessed = "processed"
+	DeliveryDuplicate = "duplicate"
+	DeliveryIgnored   = "ignored"
+)
+
+type Delivery struct {
+	Timestamp string
+	Nonce     string
+	Signature string
+}
+
+// Event is the envelope sent by detection workers. ID identifies the event
+// across retries; Data depends on Type.
+type Event struct {
+	ID      string          `json:"id" binding:"required"`
+	Type    string          `json:"type" binding:"required"`
+	VideoID uuid.UUID       `json:"video_id"`
+	Data    json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+type ProgressEventData struct {
+	Progress float64 `json:"progress" binding:"gte=0,lte=100"`
+	Message  string  `json:"message"`
+}
+
+type FailedEventData struct {
+	Reason string `json:"reason"`
+}
+
+type DeliveryResult struct {
+	EventID string `json:"event_id"`
+	Status  string `json:"status"`
+}
@@ -13,10 +13,18 @@ import (
 type Model interface {
 }
 
+// Versioned models carry an optimistic concurrency version which every
+// update through the base repository increments.
+type Versioned interface {
+	GetVersion() int
+	SetVersion(version int)
+}
+
 type Clause func(tx *gorm.DB)
 
 type BaseRepository[M Model] interface {
 	List(ctx context.Context, params models.QueryParams, clauses ...Clause) ([]*M, error)
+	ListByCursor(ctx context.Context, params models.QueryParams, cursor string, clauses ...Clause) (*CursorPage[M], error)
 	GetByID(ctx context.Context, id interface{}) (*M, error)
 	Count(ctx context.Context, params models.QueryParams, clauses ...Clause) (int64, error)
 	Create(ctx context.Context, o *M) (*M, error)
@@ -62,8 +70,18 @@ func (b *baseRepository[M]) List(ctx context.Context, params models.QueryParams,
 	if params.Limit > 0 {
 		tx = tx.Limit(params.Limit)
 	}
-	if len(params.QuerySort.Parse()) > 0 {
-		tx = tx.Order(params.QuerySort.Parse())
+	fields, err := sortFields(b.model, params.QuerySort)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		table, err := tableOf(b.db, b.model)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			tx = tx.Order(orderByColumn(tx, table, f.Column, f.Desc, f.Nulls))
+		}
 	}
 
 	if params.Selected != nil {
@@ -77,7 +95,7 @@ func (b *baseRepository[M]) List(ctx context.Context, params models.QueryParams,
 	for _, f := range clauses {
 		f(tx)
 	}
-	err := tx.Find(&oList).Error
+	err = tx.Find(&oList).Error
 	if err != nil {
 		log.Printf("DEBUG List: GORM error: %v", err)
 		return nil, err
@@ -109,8 +127,9 @@ func (b *baseRepository[M]) Count(ctx context.Context, params models.QueryParams
 		return count, nil
 	}
 
-	// Use Table() instead of Model() to avoid generic type issues
-	tx := b.db.Table(tableName)
+	// Use Table() instead of Model() to avoid generic type issues; the model is
+	// still set so that its scopes (e.g. soft delete) apply like in List.
+	tx := b.db.Model(b.model).Table(tableName)
 	for _, f := range clauses {
 		f(tx)
 	}
@@ -143,26 +162,42 @@ func (b *baseRepository[M]) Create(ctx context.Context, o *M) (*M, error) {
 	return o, nil
 }
 
+// Update writes the non-zero fields of o. For a Versioned model, o must carry
+// the version it was read at: the row is only updated if it still has that
+// version, which is incremented in the same statement, and
+// common.ErrVersionConflict is returned otherwise.
 func (b *baseRepository[M]) Update(ctx context.Context, id interface{}, o *M, clauses ...Clause) (*M, error) {
 	updatedObj := new(M)
 	tx := b.db.Model(updatedObj).Clauses(clause.Returning{})
 	for _, f := range clauses {
 		f(tx)
 	}
-	err := tx.Where("id = ?", id).Updates(o).Error
-	if err != nil {
-		return nil, err
+	versioned, isVersioned := any(o).(Versioned)
+	if isVersioned {
+		expected := versioned.GetVersion()
+		versioned.SetVersion(expected + 1)
+		tx = tx.Where("version = ?", expected)
+	}
+	res := tx.Where("id = ?", id).Updates(o)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if isVersioned && res.RowsAffected == 0 {
+		return nil, common.ErrVersionConflict
 	}
 	return updatedObj, nil
 }
 
+// UpdateColumns writes columns as given, zero values included. The version of
+// a Versioned model is incremented unconditionally; callers check it against
+// a row locked with SELECT ... FOR UPDATE when the update is conditional.
 func (b *baseRepository[M]) UpdateColumns(ctx context.Context, id interface{}, columns map[string]interface{}, clauses ...Clause) (*M, error) {
 	updatedObj := new(M)
 	tx := b.db.Model(updatedObj).Clauses(clause.Returning{})
 	for _, f := range clauses {
 		f(tx)
 	}
-	err := tx.Where("id = ?", id).Updates(columns).Error
+	err := tx.Where("id = ?", id).Updates(withVersionBump[M](columns)).Error
 	if err != nil {
 		return nil, err
 	}
@@ -256,10 +291,23 @@ func (b *baseRepository[M]) UpdatesColumnsByConditions(ctx context.Context, colu
 	for _, f := range clauses {
 		f(tx)
 	}
-	err := tx.Updates(columns).Error
+	err := tx.Updates(withVersionBump[M](columns)).Error
 	return err
 }
 
+// withVersionBump adds the version increment of a Versioned model to columns.
+func withVersionBump[M Model](columns map[string]interface{}) map[string]interface{} {
+	if _, ok := any(new(M)).(Versioned); !ok {
+		return columns
+	}
+	bumped := make(map[string]interface{}, len(columns)+1)
+	for column, value := range columns {
+		bumped[column] = value
+	}
+	bumped["version"] = gorm.Expr("version + 1")
+	return bumped
+}
+
 func (b *baseRepository[M]) ExecRaw(ctx context.Context, raw string) error {
 	return b.db.Exec(raw).Error
 }
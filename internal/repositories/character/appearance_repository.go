@@ -2,22 +2,22 @@ package character
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"smart-scene-app-api/internal/models"
+	"smart-scene-app-api/common"
 	"smart-scene-app-api/internal/models/character"
 	"smart-scene-app-api/internal/repositories"
 
-	"math"
-	"sort"
-	"strings"
-
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AppearanceRepository interface {
 	repositories.BaseRepository[character.CharacterAppearance]
-	FindTimeSegmentsWithCharacters(ctx context.Context, videoID uuid.UUID, includeCharacters, excludeCharacters []uuid.UUID) ([]character.TimeSegmentResult, error)
+	ListSceneAppearances(ctx context.Context, videoID uuid.UUID, minConfidence float64) ([]character.SceneAppearance, error)
+	GetByIDForUpdate(ctx context.Context, videoID, id uuid.UUID) (*character.CharacterAppearance, error)
+	ListReviewed(ctx context.Context, videoID uuid.UUID) ([]character.CharacterAppearance, error)
+	SummarizeVideoCharacters(ctx context.Context, videoID uuid.UUID, filter character.VideoCharacterSummaryFilter) ([]character.VideoCharacterSummary, int64, error)
 }
 
 type appearanceRepository struct {
@@ -32,272 +32,129 @@ func NewAppearanceRepository(db *gorm.DB) AppearanceRepository {
 	}
 }
 
-func (r *appearanceRepository) FindTimeSegmentsWithCharacters(ctx context.Context, videoID uuid.UUID, includeCharacters, excludeCharacters []uuid.UUID) ([]character.TimeSegmentResult, error) {
-	if len(includeCharacters) == 0 {
-		return []character.TimeSegmentResult{}, nil
-	}
-
-	fmt.Printf("[DEBUG] Repository SQL Query Parameters - VideoID: %s, Include: %v, Exclude: %v\n",
-		videoID, includeCharacters, excludeCharacters)
-
-	includeSet := make(map[uuid.UUID]bool)
-	for _, id := range includeCharacters {
-		includeSet[id] = true
-	}
-
-	for _, id := range excludeCharacters {
-		if includeSet[id] {
-			// If same character ID is in both include and exclude, return empty result
-			fmt.Printf("[DEBUG] Character ID %s found in both include and exclude lists, returning empty result\n", id)
-			return []character.TimeSegmentResult{}, nil
-		}
-	}
-
-	// Step 1: Get all segments with include characters
-	includeSegments, err := r.getSegmentsWithCharacters(ctx, videoID, includeCharacters)
+// GetByIDForUpdate locks an appearance of a video until the surrounding
+// transaction ends. It must be called on a repository built from a
+// transaction handle.
+func (r *appearanceRepository) GetByIDForUpdate(ctx context.Context, videoID, id uuid.UUID) (*character.CharacterAppearance, error) {
+	var a character.CharacterAppearance
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&a, "id = ? AND video_id = ?", id, videoID).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get include segments: %w", err)
+		return nil, err
 	}
+	return &a, nil
+}
 
-	// Step 2: If no exclude characters, return include segments as-is
-	if len(excludeCharacters) == 0 {
-		return includeSegments, nil
-	}
-
-	// Step 3: Get all segments with exclude characters
-	excludeSegments, err := r.getSegmentsWithCharacters(ctx, videoID, excludeCharacters)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get exclude segments: %w", err)
-	}
-
-	// Step 4: Cut overlap between include and exclude segments
-	filteredSegments := r.cutOverlapFromSegments(includeSegments, excludeSegments)
-
-	fmt.Printf("[DEBUG] Final filtered segments: %d\n", len(filteredSegments))
-	return filteredSegments, nil
+// ListReviewed returns the confirmed and rejected appearances of a video, the
+// ones an ingestion keeps.
+func (r *appearanceRepository) ListReviewed(ctx context.Context, videoID uuid.UUID) ([]character.CharacterAppearance, error) {
+	var appearances []character.CharacterAppearance
+	err := r.db.WithContext(ctx).
+		Where("video_id = ? AND (is_confirmed OR is_rejected)", videoID).
+		Order("start_time ASC, id ASC").
+		Find(&appearances).Error
+	return appearances, err
 }
 
-// getSegmentsWithCharacters gets time segments containing specific characters
-func (r *appearanceRepository) getSegmentsWithCharacters(ctx context.Context, videoID uuid.UUID, characterIDs []uuid.UUID) ([]character.TimeSegmentResult, error) {
-	if len(characterIDs) == 0 {
-		return []character.TimeSegmentResult{}, nil
-	}
+// ListSceneAppearances returns the appearances of a video the scene engine
+// works on: those of active characters detected with at least minConfidence,
+// rejected ones excluded.
+func (r *appearanceRepository) ListSceneAppearances(ctx context.Context, videoID uuid.UUID, minConfidence float64) ([]character.SceneAppearance, error) {
+	var appearances []character.SceneAppearance
+	err := r.db.WithContext(ctx).
+		Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+" ca").
+		Select(`ca.character_id, c.name AS character_name, COALESCE(c.avatar, '') AS character_avatar,
+			ca.start_time, ca.end_time, ca.confidence`).
+		Joins("JOIN characters c ON c.id = ca.character_id AND c.is_active = true").
+		Where("ca.video_id = ? AND NOT ca.is_rejected AND ca.confidence >= ?", videoID, minConfidence).
+		Order("ca.start_time ASC, ca.character_id ASC").
+		Scan(&appearances).Error
+	return appearances, err
+}
 
-	query := `
-		SELECT 
-			start_time,
-			end_time,
-			duration,
-			COUNT(*) as character_count,
-			JSON_AGG(
-				JSON_BUILD_OBJECT(
-					'character_id', ca.character_id::text,
-					'character_name', COALESCE(c.name, ''),
-					'character_avatar', COALESCE(c.avatar, ''),
-					'confidence', COALESCE(ca.confidence, 0.0)
-				)
-			) as characters
-		FROM character_appearances ca
-		JOIN characters c ON c.id = ca.character_id AND c.is_active = true
-		WHERE ca.video_id = $1 AND ca.character_id IN (%s)
-		GROUP BY start_time, end_time, duration
-		ORDER BY start_time
-	`
+// screenTimeSQL is the time a row of the spans of SummarizeVideoCharacters
+// adds to the screen time of its character: the part of the appearance not
+// covered by the ones starting before it.
+const screenTimeSQL = "GREATEST(s.end_time - GREATEST(s.start_time, COALESCE(s.covered_until, s.start_time)), 0)"
 
-	// Build placeholders for character IDs
-	placeholders := make([]string, len(characterIDs))
-	args := []interface{}{videoID}
-	for i, id := range characterIDs {
-		placeholders[i] = fmt.Sprintf("$%d", len(args)+1)
-		args = append(args, id)
+// SummarizeVideoCharacters aggregates the appearances of the active characters
+// of a video, rejected ones excluded, one row per character, with the total
+// number of rows that pass filter. The sort defaults to appearance_count.desc.
+func (r *appearanceRepository) SummarizeVideoCharacters(ctx context.Context, videoID uuid.UUID, filter character.VideoCharacterSummaryFilter) ([]character.VideoCharacterSummary, int64, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = "appearance_count.desc"
 	}
-
-	finalQuery := fmt.Sprintf(query, strings.Join(placeholders, ","))
-	fmt.Printf("[DEBUG] Query: %s\n", finalQuery)
-	fmt.Printf("[DEBUG] Args: %v\n", args)
-
-	rows, err := r.db.Raw(finalQuery, args...).Rows()
+	fields, err := models.QuerySort{Origin: sort}.Parse(character.VideoCharacterSummary{}.SortColumns())
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
-	}
-	defer rows.Close()
-
-	var results []character.TimeSegmentResult
-
-	for rows.Next() {
-		var startTime, endTime, duration float64
-		var characterCount int
-		var charactersJSON string
-
-		err := rows.Scan(&startTime, &endTime, &duration, &characterCount, &charactersJSON)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan query result row: %w", err)
-		}
-
-		// Parse characters JSON
-		var charactersData []map[string]interface{}
-		if err := json.Unmarshal([]byte(charactersJSON), &charactersData); err != nil {
-			return nil, fmt.Errorf("failed to parse characters JSON: %w", err)
-		}
-
-		// Convert to characters in segment
-		var characters []character.CharacterInSegment
-		for _, char := range charactersData {
-			if char == nil {
-				continue
-			}
-
-			characterIDStr, ok := char["character_id"].(string)
-			if !ok {
-				return nil, fmt.Errorf("failed to parse character_id as string")
-			}
-
-			charID, err := uuid.Parse(characterIDStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse character ID: %w", err)
-			}
-
-			confidence, ok := char["confidence"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("failed to parse confidence value")
-			}
-
-			characterName, ok := char["character_name"].(string)
-			if !ok {
-				return nil, fmt.Errorf("failed to parse character name")
-			}
-
-			characterAvatar, ok := char["character_avatar"].(string)
-			if !ok {
-				characterAvatar = ""
-			}
-
-			characters = append(characters, character.CharacterInSegment{
-				CharacterID:     charID,
-				CharacterName:   characterName,
-				CharacterAvatar: characterAvatar,
-				Confidence:      confidence,
-			})
+		return nil, 0, err
+	}
+
+	base := func() *gorm.DB {
+		// covered_until is the furthest end of the earlier appearances of the
+		// same character, so overlaps are counted once in the screen time.
+		spans := r.db.
+			Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+" ca").
+			Select(`ca.video_id, ca.character_id, ca.start_time, ca.end_time, ca.confidence,
+				MAX(ca.end_time) OVER (PARTITION BY ca.character_id ORDER BY ca.start_time, ca.end_time
+					ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) AS covered_until`).
+			Where("ca.video_id = ? AND NOT ca.is_rejected AND ca.confidence >= ?", videoID, filter.MinConfidence)
+
+		tx := r.db.WithContext(ctx).
+			Table("(?) AS s", spans).
+			Joins("JOIN characters c ON c.id = s.character_id AND c.is_active = true").
+			Joins("JOIN videos v ON v.id = s.video_id").
+			Group("s.video_id, s.character_id, c.name, c.avatar, v.duration")
+		if filter.CharacterName != "" {
+			tx.Where("c.name ILIKE ?", "%"+filter.CharacterName+"%")
 		}
-
-		result := character.TimeSegmentResult{
-			StartTime:       startTime,
-			EndTime:         endTime,
-			Duration:        duration,
-			TotalCharacters: characterCount,
-			Characters:      characters,
+		if filter.MinAppearances > 0 {
+			tx.Having("COUNT(*) >= ?", filter.MinAppearances)
 		}
-
-		results = append(results, result)
-	}
-
-	return results, nil
-}
-
-// cutOverlapFromSegments cuts overlapping portions from include segments where exclude segments exist
-func (r *appearanceRepository) cutOverlapFromSegments(includeSegments, excludeSegments []character.TimeSegmentResult) []character.TimeSegmentResult {
-	var filteredSegments []character.TimeSegmentResult
-
-	for _, includeSegment := range includeSegments {
-		fmt.Printf("[DEBUG] Processing include segment: %.1f-%.1f\n", includeSegment.StartTime, includeSegment.EndTime)
-
-		// Find all exclude segments that overlap with this include segment
-		var overlappingExcludes []character.TimeSegmentResult
-		for _, excludeSegment := range excludeSegments {
-			if r.segmentsOverlap(includeSegment, excludeSegment) {
-				overlappingExcludes = append(overlappingExcludes, excludeSegment)
-				fmt.Printf("[DEBUG] Found overlapping exclude segment: %.1f-%.1f\n", excludeSegment.StartTime, excludeSegment.EndTime)
-			}
+		return tx
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS g", base().Select("s.character_id")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []character.VideoCharacterSummary{}, 0, nil
+	}
+
+	tx := base().
+		Select(`s.video_id, s.character_id, c.name AS character_name, COALESCE(c.avatar, '') AS character_avatar,
+			COUNT(*) AS appearance_count,
+			SUM(`+screenTimeSQL+`) AS total_duration,
+			MIN(s.start_time) AS first_appearance,
+			MAX(s.end_time) AS last_appearance,
+			AVG(s.confidence) AS average_confidence,
+			MAX(s.confidence) AS max_confidence,
+			LEAST(SUM(`+screenTimeSQL+`) / NULLIF(v.duration, 0), 1) AS screen_time_share`)
+	for _, f := range fields {
+		order := tx.Statement.Quote(f.Column)
+		if f.Desc {
+			order += " DESC"
+		} else {
+			order += " ASC"
 		}
-
-		// If no overlapping excludes, keep the include segment as-is
-		if len(overlappingExcludes) == 0 {
-			filteredSegments = append(filteredSegments, includeSegment)
-			fmt.Printf("[DEBUG] No overlaps, keeping segment: %.1f-%.1f\n", includeSegment.StartTime, includeSegment.EndTime)
-			continue
-		}
-
-		// Cut the include segment based on overlapping excludes
-		cutSegments := r.cutSegmentWithExcludes(includeSegment, overlappingExcludes)
-		filteredSegments = append(filteredSegments, cutSegments...)
-
-		for _, cutSegment := range cutSegments {
-			fmt.Printf("[DEBUG] Cut segment result: %.1f-%.1f\n", cutSegment.StartTime, cutSegment.EndTime)
+		switch f.Nulls {
+		case models.NullsFirst:
+			order += " NULLS FIRST"
+		case models.NullsLast:
+			order += " NULLS LAST"
 		}
+		tx.Order(order)
 	}
 
-	return filteredSegments
-}
-
-// segmentsOverlap checks if two time segments overlap
-func (r *appearanceRepository) segmentsOverlap(seg1, seg2 character.TimeSegmentResult) bool {
-	return seg1.StartTime <= seg2.EndTime && seg2.StartTime <= seg1.EndTime
-}
-
-// cutSegmentWithExcludes cuts an include segment by removing overlapping portions with exclude segments
-func (r *appearanceRepository) cutSegmentWithExcludes(includeSegment character.TimeSegmentResult, excludeSegments []character.TimeSegmentResult) []character.TimeSegmentResult {
-	var results []character.TimeSegmentResult
-	currentStart := includeSegment.StartTime
-	currentEnd := includeSegment.EndTime
-
-	// Sort exclude segments by start time
-	sort.Slice(excludeSegments, func(i, j int) bool {
-		return excludeSegments[i].StartTime < excludeSegments[j].StartTime
-	})
-
-	for _, exclude := range excludeSegments {
-		// Calculate overlap
-		overlapStart := math.Max(currentStart, exclude.StartTime)
-		overlapEnd := math.Min(currentEnd, exclude.EndTime)
-
-		// If there's actual overlap
-		if overlapStart < overlapEnd {
-			// Add segment before overlap (if any)
-			if currentStart < overlapStart {
-				beforeSegment := character.TimeSegmentResult{
-					StartTime:       currentStart,
-					EndTime:         overlapStart,
-					Duration:        overlapStart - currentStart,
-					TotalCharacters: includeSegment.TotalCharacters,
-					Characters:      includeSegment.Characters,
-				}
-				results = append(results, beforeSegment)
-				fmt.Printf("[DEBUG] Added before-overlap segment: %.1f-%.1f\n", beforeSegment.StartTime, beforeSegment.EndTime)
-			}
-
-			// Move current start to after the overlap
-			currentStart = overlapEnd
-		}
-	}
-
-	// Add remaining segment after all overlaps (if any)
-	if currentStart < currentEnd {
-		afterSegment := character.TimeSegmentResult{
-			StartTime:       currentStart,
-			EndTime:         currentEnd,
-			Duration:        currentEnd - currentStart,
-			TotalCharacters: includeSegment.TotalCharacters,
-			Characters:      includeSegment.Characters,
-		}
-		results = append(results, afterSegment)
-		fmt.Printf("[DEBUG] Added after-overlap segment: %.1f-%.1f\n", afterSegment.StartTime, afterSegment.EndTime)
-	}
-
-	return results
-}
-
-// joinStrings joins string slices with a separator
-func joinStrings(strs []string, sep string) string {
-	if len(strs) == 0 {
-		return ""
-	}
-	if len(strs) == 1 {
-		return strs[0]
-	}
-
-	result := strs[0]
-	for i := 1; i < len(strs); i++ {
-		result += sep + strs[i]
+	var summaries []character.VideoCharacterSummary
+	err = tx.Order("s.character_id ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, 0, err
 	}
-	return result
+	return summaries, total, nil
 }
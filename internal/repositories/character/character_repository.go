@@ -1,16 +1,192 @@
 package character
 
 import (
+	"context"
+	"smart-scene-app-api/common"
 	"smart-scene-app-api/internal/models/character"
 	"smart-scene-app-api/internal/repositories"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	repositories.BaseRepository[character.Character]
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*character.Character, error)
+	ExistsByName(ctx context.Context, name string, exceptID uuid.UUID) (bool, error)
+	CountUsage(ctx context.Context, characterIDs []uuid.UUID, clauses ...repositories.Clause) (map[uuid.UUID]character.CharacterUsage, error)
+	ListVideoUsage(ctx context.Context, characterID uuid.UUID, limit, offset int, clauses ...repositories.Clause) ([]character.CharacterVideoUsage, int64, error)
+	IsReferenced(ctx context.Context, id uuid.UUID) (bool, error)
+	DeleteWithUsage(ctx context.Context, id uuid.UUID) error
+	RecountVideoCharacters(ctx context.Context, videoIDs []uuid.UUID) error
+}
+
+type repository struct {
+	repositories.BaseRepository[character.Character]
+	db *gorm.DB
 }
 
 func NewRepository(db *gorm.DB) Repository {
-	return repositories.NewBaseRepository[character.Character](db)
+	return &repository{
+		BaseRepository: repositories.NewBaseRepository[character.Character](db),
+		db:             db,
+	}
+}
+
+// GetByIDForUpdate locks the character row until the surrounding transaction
+// ends. It must be called on a repository built from a transaction handle.
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*character.Character, error) {
+	var c character.Character
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&c, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ExistsByName reports whether another character than exceptID has name,
+// compared case-insensitively.
+func (r *repository) ExistsByName(ctx context.Context, name string, exceptID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&character.Character{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountUsage counts the videos and appearances of characters, rejected
+// appearances and trashed videos excluded. clauses filter the videos table.
+func (r *repository) CountUsage(ctx context.Context, characterIDs []uuid.UUID, clauses ...repositories.Clause) (map[uuid.UUID]character.CharacterUsage, error) {
+	usage := make(map[uuid.UUID]character.CharacterUsage, len(characterIDs))
+	if len(characterIDs) == 0 {
+		return usage, nil
+	}
+
+	var rows []struct {
+		CharacterID     uuid.UUID
+		VideoCount      int
+		AppearanceCount int
+	}
+	tx := r.db.WithContext(ctx).
+		Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+" ca").
+		Joins("JOIN videos ON videos.id = ca.video_id AND videos.deleted_at IS NULL").
+		Select("ca.character_id, COUNT(DISTINCT ca.video_id) AS video_count, COUNT(*) AS appearance_count").
+		Where("ca.character_id IN ? AND NOT ca.is_rejected", characterIDs).
+		Group("ca.character_id")
+	for _, f := range clauses {
+		f(tx)
+	}
+	err := tx.Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		usage[row.CharacterID] = character.CharacterUsage{
+			VideoCount:      row.VideoCount,
+			AppearanceCount: row.AppearanceCount,
+		}
+	}
+	return usage, nil
+}
+
+// ListVideoUsage lists the videos a character appears in, most recently
+// created first, with the total number of such videos. clauses filter the
+// videos table. The total duration counts overlapping appearances once, as
+// SummarizeVideoCharacters does.
+func (r *repository) ListVideoUsage(ctx context.Context, characterID uuid.UUID, limit, offset int, clauses ...repositories.Clause) ([]character.CharacterVideoUsage, int64, error) {
+	base := func() *gorm.DB {
+		spans := r.db.
+			Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+" ca").
+			Select(`ca.video_id, ca.start_time, ca.end_time,
+				MAX(ca.end_time) OVER (PARTITION BY ca.video_id ORDER BY ca.start_time, ca.end_time
+					ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) AS covered_until`).
+			Where("ca.character_id = ? AND NOT ca.is_rejected", characterID)
+
+		tx := r.db.WithContext(ctx).
+			Table("(?) AS s", spans).
+			Joins("JOIN videos ON videos.id = s.video_id AND videos.deleted_at IS NULL")
+		for _, f := range clauses {
+			f(tx)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := base().Distinct("s.video_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var usage []character.CharacterVideoUsage
+	err := base().
+		Select(`videos.id AS video_id, videos.title, COALESCE(videos.thumbnail_url, '') AS thumbnail_url, videos.status,
+			COUNT(*) AS appearance_count,
+			COALESCE(SUM(` + screenTimeSQL + `), 0) AS total_duration,
+			MIN(s.start_time) AS first_appearance,
+			MAX(s.end_time) AS last_appearance`).
+		Group("videos.id").
+		Order("videos.created_at DESC, videos.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&usage).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return usage, total, nil
+}
+
+// IsReferenced reports whether an appearance, rejected or not, or a video tag
+// refers to a character.
+func (r *repository) IsReferenced(ctx context.Context, id uuid.UUID) (bool, error) {
+	var referenced bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM `+common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+` WHERE character_id = ?)
+		OR EXISTS (SELECT 1 FROM `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+` WHERE character_id = ?)`, id, id).
+		Scan(&referenced).Error
+	return referenced, err
+}
+
+// DeleteWithUsage deletes a character with its appearances and its video tag
+// attachments, keeping tags.usage_count and the character_count of the
+// affected videos in step. It must be called on a repository built from a
+// transaction handle.
+func (r *repository) DeleteWithUsage(ctx context.Context, id uuid.UUID) error {
+	db := r.db.WithContext(ctx)
+
+	err := db.Exec(`UPDATE `+common.POSTGRES_TABLE_NAME_TAGS+` t
+		SET usage_count = GREATEST(t.usage_count - d.n, 0)
+		FROM (SELECT tag_id, COUNT(*) AS n FROM `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+` WHERE character_id = ? GROUP BY tag_id) d
+		WHERE t.id = d.tag_id`, id).Error
+	if err != nil {
+		return err
+	}
+	if err := db.Exec(`DELETE FROM `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+` WHERE character_id = ?`, id).Error; err != nil {
+		return err
+	}
+
+	var videoIDs []uuid.UUID
+	err = db.Raw(`DELETE FROM `+common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+` WHERE character_id = ? RETURNING video_id`, id).
+		Scan(&videoIDs).Error
+	if err != nil {
+		return err
+	}
+	if err := r.RecountVideoCharacters(ctx, videoIDs); err != nil {
+		return err
+	}
+
+	return db.Exec(`DELETE FROM `+common.POSTGRES_TABLE_NAME_CHARACTERS+` WHERE id = ?`, id).Error
+}
+
+// RecountVideoCharacters recomputes the character_count of videos after
+// their appearances changed, incrementing their version.
+func (r *repository) RecountVideoCharacters(ctx context.Context, videoIDs []uuid.UUID) error {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`UPDATE `+common.POSTGRES_TABLE_NAME_VIDEOS+` v
+		SET character_count = (SELECT COUNT(DISTINCT ca.character_id) FROM `+common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+` ca WHERE ca.video_id = v.id AND NOT ca.is_rejected),
+			version = v.version + 1,
+			updated_at = now()
+		WHERE v.id IN ?`, videoIDs).Error
 }
@@ -0,0 +1,127 @@
+package character
+
+import (
+	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/character"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MergeRepository stores character merges and moves the rows attached to
+// characters between them. Its write methods must be called on a repository
+// built from a transaction handle.
+type MergeRepository interface {
+	repositories.BaseRepository[character.CharacterMerge]
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*character.CharacterMerge, error)
+	ListAppearances(ctx context.Context, characterIDs []uuid.UUID) ([]character.MergedAppearance, error)
+	ReassignAppearances(ctx context.Context, ids []uuid.UUID, from, to uuid.UUID) error
+	ListVideoTags(ctx context.Context, characterIDs []uuid.UUID) ([]character.MergedVideoTag, error)
+	ReassignVideoTags(ctx context.Context, ids []int, from, to uuid.UUID) error
+	DeleteVideoTags(ctx context.Context, ids []int) error
+	RestoreVideoTags(ctx context.Context, videoTags []character.MergedVideoTag) (map[int]int, error)
+	RestoreCharacters(ctx context.Context, characters []character.Character) error
+}
+
+type mergeRepository struct {
+	repositories.BaseRepository[character.CharacterMerge]
+	db *gorm.DB
+}
+
+func NewMergeRepository(db *gorm.DB) MergeRepository {
+	return &mergeRepository{
+		BaseRepository: repositories.NewBaseRepository[character.CharacterMerge](db),
+		db:             db,
+	}
+}
+
+func (r *mergeRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*character.CharacterMerge, error) {
+	var m character.CharacterMerge
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&m, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mergeRepository) ListAppearances(ctx context.Context, characterIDs []uuid.UUID) ([]character.MergedAppearance, error) {
+	var appearances []character.MergedAppearance
+	err := r.db.WithContext(ctx).
+		Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES).
+		Select("id, character_id, video_id").
+		Where("character_id IN ?", characterIDs).
+		Order("id").
+		Scan(&appearances).Error
+	return appearances, err
+}
+
+// ReassignAppearances moves the given appearances of from to to; appearances
+// no longer attached to from are left alone.
+func (r *mergeRepository) ReassignAppearances(ctx context.Context, ids []uuid.UUID, from, to uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`UPDATE `+common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+`
+		SET character_id = ? WHERE id IN ? AND character_id = ?`, to, ids, from).Error
+}
+
+func (r *mergeRepository) ListVideoTags(ctx context.Context, characterIDs []uuid.UUID) ([]character.MergedVideoTag, error) {
+	var videoTags []character.MergedVideoTag
+	err := r.db.WithContext(ctx).
+		Table(common.POSTGRES_TABLE_NAME_VIDEO_TAGS).
+		Select("id, video_id, tag_id, character_id").
+		Where("character_id IN ?", characterIDs).
+		Order("id").
+		Scan(&videoTags).Error
+	return videoTags, err
+}
+
+// ReassignVideoTags moves the given video tags of from to to; video tags no
+// longer attached to from are left alone.
+func (r *mergeRepository) ReassignVideoTags(ctx context.Context, ids []int, from, to uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`UPDATE `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+`
+		SET character_id = ? WHERE id IN ? AND character_id = ?`, to, ids, from).Error
+}
+
+func (r *mergeRepository) DeleteVideoTags(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`DELETE FROM `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+` WHERE id IN ?`, ids).Error
+}
+
+// RestoreVideoTags re-inserts deleted video tags with their original IDs,
+// skipping those that conflict with a current row, and returns the number of
+// rows restored per tag.
+func (r *mergeRepository) RestoreVideoTags(ctx context.Context, videoTags []character.MergedVideoTag) (map[int]int, error) {
+	restored := make(map[int]int)
+	db := r.db.WithContext(ctx)
+	for _, vt := range videoTags {
+		res := db.Exec(`INSERT INTO `+common.POSTGRES_TABLE_NAME_VIDEO_TAGS+` (id, video_id, tag_id, character_id)
+			VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`, vt.ID, vt.VideoID, vt.TagID, vt.CharacterID)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected > 0 {
+			restored[vt.TagID]++
+		}
+	}
+	return restored, nil
+}
+
+// RestoreCharacters re-inserts deleted characters with all their columns,
+// zero values included.
+func (r *mergeRepository) RestoreCharacters(ctx context.Context, characters []character.Character) error {
+	if len(characters) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Select("*").Create(&characters).Error
+}
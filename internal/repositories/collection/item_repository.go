@@ -0,0 +1,54 @@
+package collection
+
+import (
+	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/collection"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type ItemRepository interface {
+	repositories.BaseRepository[collection.CollectionItem]
+	ListByCollectionID(ctx context.Context, collectionID uuid.UUID) ([]collection.CollectionItem, error)
+	SetPositions(ctx context.Context, collectionID uuid.UUID, itemIDs []uuid.UUID) error
+}
+
+type itemRepository struct {
+	repositories.BaseRepository[collection.CollectionItem]
+	db *gorm.DB
+}
+
+func NewItemRepository(db *gorm.DB) ItemRepository {
+	return &itemRepository{
+		BaseRepository: repositories.NewBaseRepository[collection.CollectionItem](db),
+		db:             db,
+	}
+}
+
+func (r *itemRepository) ListByCollectionID(ctx context.Context, collectionID uuid.UUID) ([]collection.CollectionItem, error) {
+	var items []collection.CollectionItem
+	err := r.db.WithContext(ctx).
+		Where("collection_id = ?", collectionID).
+		Order("position ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// SetPositions numbers the items of a collection from 0 in the order of
+// itemIDs. The unique (collection_id, position) constraint is deferred, so
+// this must run in a transaction when positions are swapped. itemIDs is bound
+// as one array parameter; gorm would expand a slice into a row constructor.
+func (r *itemRepository) SetPositions(ctx context.Context, collectionID uuid.UUID, itemIDs []uuid.UUID) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`UPDATE `+common.POSTGRES_TABLE_NAME_COLLECTION_ITEMS+` ci
+		SET position = o.ordinality - 1
+		FROM unnest(?::uuid[]) WITH ORDINALITY AS o(id, ordinality)
+		WHERE ci.id = o.id AND ci.collection_id = ? AND ci.position <> o.ordinality - 1`,
+		pq.Array(itemIDs), collectionID).Error
+}
@@ -0,0 +1,109 @@
+package collection
+
+import (
+	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/collection"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	repositories.BaseRepository[collection.Collection]
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*collection.Collection, error)
+	CountItems(ctx context.Context, collectionIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	DeleteWithItems(ctx context.Context, id uuid.UUID) error
+}
+
+type repository struct {
+	repositories.BaseRepository[collection.Collection]
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{
+		BaseRepository: repositories.NewBaseRepository[collection.Collection](db),
+		db:             db,
+	}
+}
+
+// VisibleTo filters the collections viewer can see: their own,
+// organization-wide ones and shared ones with a share applying to them.
+func VisibleTo(viewer common.Viewer) repositories.Clause {
+	return func(tx *gorm.DB) {
+		if viewer.Admin {
+			return
+		}
+		tx.Where(`(collections.created_by = @user
+			OR collections.visibility = @organization
+			OR (collections.visibility = @shared AND EXISTS (
+				SELECT 1 FROM collection_shares cs
+				WHERE cs.collection_id = collections.id AND (cs.user_id = @user OR cs.role_id = @role)
+			)))`, map[string]interface{}{
+			"user":         viewer.UserID,
+			"role":         viewer.RoleID,
+			"organization": videoModel.VisibilityOrganization,
+			"shared":       videoModel.VisibilityShared,
+		})
+	}
+}
+
+// GetByIDForUpdate locks the collection row until the surrounding transaction
+// ends, serializing writes to its items. It must be called on a repository
+// built from a transaction handle.
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*collection.Collection, error) {
+	var c collection.Collection
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&c, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) CountItems(ctx context.Context, collectionIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(collectionIDs))
+	if len(collectionIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CollectionID uuid.UUID
+		Count        int
+	}
+	err := r.db.WithContext(ctx).
+		Table(common.POSTGRES_TABLE_NAME_COLLECTION_ITEMS).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIDs).
+		Group("collection_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteWithItems deletes a collection with its items and shares. It must be
+// called on a repository built from a transaction handle.
+func (r *repository) DeleteWithItems(ctx context.Context, id uuid.UUID) error {
+	db := r.db.WithContext(ctx)
+	statements := []string{
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_COLLECTION_ITEMS + ` WHERE collection_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_COLLECTION_SHARES + ` WHERE collection_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_COLLECTIONS + ` WHERE id = ?`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
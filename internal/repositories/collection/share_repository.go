@@ -0,0 +1,65 @@
+package collection
+
+import (
+	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/collection"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareRepository interface {
+	repositories.BaseRepository[collection.CollectionShare]
+	ListByCollectionID(ctx context.Context, collectionID uuid.UUID) ([]collection.CollectionShare, error)
+	ListGrants(ctx context.Context, collectionID uuid.UUID, viewer common.Viewer) ([]collection.CollectionShare, error)
+	FindByGrantee(ctx context.Context, collectionID uuid.UUID, userID, roleID *uuid.UUID) (*collection.CollectionShare, error)
+}
+
+type shareRepository struct {
+	repositories.BaseRepository[collection.CollectionShare]
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) ShareRepository {
+	return &shareRepository{
+		BaseRepository: repositories.NewBaseRepository[collection.CollectionShare](db),
+		db:             db,
+	}
+}
+
+func (r *shareRepository) ListByCollectionID(ctx context.Context, collectionID uuid.UUID) ([]collection.CollectionShare, error) {
+	var shares []collection.CollectionShare
+	err := r.db.WithContext(ctx).
+		Where("collection_id = ?", collectionID).
+		Order("created_at ASC, id ASC").
+		Find(&shares).Error
+	return shares, err
+}
+
+// ListGrants returns the shares of a collection that apply to viewer,
+// directly or through their role.
+func (r *shareRepository) ListGrants(ctx context.Context, collectionID uuid.UUID, viewer common.Viewer) ([]collection.CollectionShare, error) {
+	var shares []collection.CollectionShare
+	err := r.db.WithContext(ctx).
+		Where("collection_id = ? AND (user_id = ? OR role_id = ?)", collectionID, viewer.UserID, viewer.RoleID).
+		Find(&shares).Error
+	return shares, err
+}
+
+// FindByGrantee returns the share of a collection with a user or a role, or
+// gorm.ErrRecordNotFound.
+func (r *shareRepository) FindByGrantee(ctx context.Context, collectionID uuid.UUID, userID, roleID *uuid.UUID) (*collection.CollectionShare, error) {
+	var share collection.CollectionShare
+	tx := r.db.WithContext(ctx).Where("collection_id = ?", collectionID)
+	if userID != nil {
+		tx = tx.Where("user_id = ?", *userID)
+	} else {
+		tx = tx.Where("role_id = ?", roleID)
+	}
+	if err := tx.Take(&share).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
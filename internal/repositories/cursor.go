@@ -0,0 +1,293 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// CursorPage is a page of a keyset-paginated listing. The cursors are opaque
+// tokens to pass back as the cursor of the next or previous page; they are
+// empty when there is no such page.
+type CursorPage[M Model] struct {
+	Items      []*M
+	NextCursor string
+	PrevCursor string
+}
+
+// cursorToken is the decoded form of a cursor: the sort it was built for, the
+// sort key of the row it points at (the primary key last), and whether it
+// pages backwards.
+type cursorToken struct {
+	Sort     string            `json:"s"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+type keysetColumn struct {
+	field      *schema.Field
+	desc       bool
+	nullsFirst bool
+}
+
+// ListByCursor lists a page of at most params.Limit rows after (or, for a
+// previous-page cursor, before) the row the cursor points at. The order is
+// params.QuerySort followed by the primary key, which makes it total.
+func (b *baseRepository[M]) ListByCursor(ctx context.Context, params models.QueryParams, cursor string, clauses ...Clause) (*CursorPage[M], error) {
+	stmt := &gorm.Statement{DB: b.db}
+	if err := stmt.Parse(b.model); err != nil {
+		return nil, err
+	}
+	fields, err := sortFields(b.model, params.QuerySort)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := keysetColumns(stmt.Schema, fields)
+	if err != nil {
+		return nil, err
+	}
+	sortKey := keysetSortKey(columns)
+
+	var token *cursorToken
+	if cursor != "" {
+		token, err = decodeCursor(cursor, sortKey, len(columns))
+		if err != nil {
+			return nil, err
+		}
+	}
+	backward := token != nil && token.Backward
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	table := stmt.Schema.Table
+	tx := b.db.Model(b.model).Table(table).Limit(limit + 1)
+	for _, c := range columns {
+		nulls := models.NullsLast
+		if c.nullsFirst != backward {
+			nulls = models.NullsFirst
+		}
+		tx = tx.Order(orderByColumn(tx, table, c.field.DBName, c.desc != backward, nulls))
+	}
+	if token != nil {
+		condition, args, err := keysetCondition(table, columns, token, backward)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where(condition, args...)
+	}
+
+	if params.Selected != nil {
+		tx.Select(params.Selected)
+	}
+	if params.Preload != nil {
+		for _, p := range params.Preload {
+			common.ApplyPreload(tx, p)
+		}
+	}
+	for _, f := range clauses {
+		f(tx)
+	}
+
+	var items []*M
+	if err := tx.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &CursorPage[M]{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	// Going forward there is a previous page whenever a cursor was given;
+	// going backward there is always a next one.
+	hasNext, hasPrev := more, token != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		if page.NextCursor, err = encodeCursor(ctx, sortKey, columns, items[len(items)-1], false); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if page.PrevCursor, err = encodeCursor(ctx, sortKey, columns, items[0], true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// keysetColumns maps the sort to model fields and appends the primary key as
+// a tie-breaker. NULL placement is made explicit, using the database default
+// (NULLS LAST ascending, NULLS FIRST descending) when not given.
+func keysetColumns(s *schema.Schema, fields []models.SortField) ([]keysetColumn, error) {
+	primary := s.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, fmt.Errorf("%w: %s has no primary key", common.ErrInvalidCursor, s.Table)
+	}
+
+	var columns []keysetColumn
+	for _, f := range fields {
+		field := s.LookUpField(f.Column)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("%w: cannot page by %q", common.ErrInvalidCursor, f.Name)
+		}
+		nullsFirst := f.Desc
+		if f.Nulls != "" {
+			nullsFirst = f.Nulls == models.NullsFirst
+		}
+		columns = append(columns, keysetColumn{field: field, desc: f.Desc, nullsFirst: nullsFirst})
+		if field == primary {
+			// The order is already total.
+			return columns, nil
+		}
+	}
+
+	// The primary key follows the direction of the last column.
+	desc := len(columns) > 0 && columns[len(columns)-1].desc
+	return append(columns, keysetColumn{field: primary, desc: desc}), nil
+}
+
+func keysetSortKey(columns []keysetColumn) string {
+	parts := make([]string, 0, len(columns))
+	for _, c := range columns {
+		part := c.field.DBName + ".asc"
+		if c.desc {
+			part = c.field.DBName + ".desc"
+		}
+		if c.nullsFirst {
+			part += ".nulls_first"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
+
+// keysetCondition builds the condition selecting the rows after the cursor in
+// the (possibly reversed) order:
+// after(c1) OR (c1 = v1 AND after(c2)) OR ...
+// where after(c) is c > v, or c < v for descending columns, extended to NULLs.
+func keysetCondition(table string, columns []keysetColumn, token *cursorToken, backward bool) (string, []interface{}, error) {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		value := reflect.New(c.field.FieldType)
+		if err := json.Unmarshal(token.Values[i], value.Interface()); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", common.ErrInvalidCursor, err)
+		}
+		if isNil(value.Elem()) {
+			values[i] = nil
+		} else {
+			values[i] = value.Elem().Interface()
+		}
+	}
+
+	var (
+		disjuncts []string
+		args      []interface{}
+		eqSQL     []string
+		eqArgs    []interface{}
+	)
+	for i, c := range columns {
+		column := fmt.Sprintf("%s.%s", table, c.field.DBName)
+		desc := c.desc != backward
+		nullsFirst := c.nullsFirst != backward
+
+		var after string
+		var afterArgs []interface{}
+		switch {
+		case values[i] == nil && nullsFirst:
+			after = column + " IS NOT NULL"
+		case values[i] == nil:
+			// Nothing sorts after NULL on this column.
+		default:
+			op := ">"
+			if desc {
+				op = "<"
+			}
+			after = fmt.Sprintf("%s %s ?", column, op)
+			if !nullsFirst {
+				after = fmt.Sprintf("(%s OR %s IS NULL)", after, column)
+			}
+			afterArgs = []interface{}{values[i]}
+		}
+
+		if after != "" {
+			disjuncts = append(disjuncts, "("+strings.Join(append(append([]string{}, eqSQL...), after), " AND ")+")")
+			args = append(append(args, eqArgs...), afterArgs...)
+		}
+
+		if values[i] == nil {
+			eqSQL = append(eqSQL, column+" IS NULL")
+		} else {
+			eqSQL = append(eqSQL, column+" = ?")
+			eqArgs = append(eqArgs, values[i])
+		}
+	}
+	if len(disjuncts) == 0 {
+		return "1 = 0", nil, nil
+	}
+	return "(" + strings.Join(disjuncts, " OR ") + ")", args, nil
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+func encodeCursor[M Model](ctx context.Context, sortKey string, columns []keysetColumn, item *M, backward bool) (string, error) {
+	token := cursorToken{Sort: sortKey, Backward: backward}
+	row := reflect.ValueOf(item).Elem()
+	for _, c := range columns {
+		value, _ := c.field.ValueOf(ctx, row)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, raw)
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor, sortKey string, columns int) (*cursorToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, common.ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(raw, &token); err != nil {
+		return nil, common.ErrInvalidCursor
+	}
+	if token.Sort != sortKey || len(token.Values) != columns {
+		return nil, fmt.Errorf("%w: the cursor was issued for another sort", common.ErrInvalidCursor)
+	}
+	return &token, nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortFields parses the sort of a listing against the SortColumns of model.
+func sortFields(model interface{}, sort models.QuerySort) ([]models.SortField, error) {
+	if sort.Origin == "" {
+		return nil, nil
+	}
+	sortable, ok := model.(models.Sortable)
+	if !ok {
+		return nil, fmt.Errorf("%w: this listing cannot be sorted", common.ErrInvalidSort)
+	}
+	return sort.Parse(sortable.SortColumns())
+}
+
+// orderByColumn renders one whitelisted sort column. The column is quoted and
+// qualified with table; NULLS FIRST/LAST is appended when asked for, which
+// clause.OrderByColumn cannot express on its own.
+func orderByColumn(tx *gorm.DB, table, column string, desc bool, nulls string) clause.OrderByColumn {
+	name := tx.Statement.Quote(clause.Column{Table: table, Name: column})
+	if desc {
+		name += " DESC"
+	} else {
+		name += " ASC"
+	}
+	switch nulls {
+	case models.NullsFirst:
+		name += " NULLS FIRST"
+	case models.NullsLast:
+		name += " NULLS LAST"
+	}
+	return clause.OrderByColumn{Column: clause.Column{Name: name, Raw: true}}
+}
+
+func tableOf(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
@@ -1,10 +1,13 @@
 package tag
 
 import (
+	"context"
 	tagModels "smart-scene-app-api/internal/models/tag"
 	"smart-scene-app-api/internal/repositories"
 	"smart-scene-app-api/server"
+	"sort"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -82,3 +85,52 @@ func NewTagPositionCategoryRepository(db *gorm.DB) *TagPositionCategoryRepo {
 		BaseRepository: baseRepo,
 	}
 }
+
+type VideoTagRepo struct {
+	db *gorm.DB
+	repositories.BaseRepository[tagModels.VideoTag]
+}
+
+func NewVideoTagRepository(db *gorm.DB) *VideoTagRepo {
+	baseRepo := repositories.NewBaseRepository[tagModels.VideoTag](db)
+	return &VideoTagRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+// ListByVideoID returns the tags of a video with their tag and category loaded.
+func (r *VideoTagRepo) ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]tagModels.VideoTag, error) {
+	var videoTags []tagModels.VideoTag
+	err := r.db.WithContext(ctx).
+		Preload("Tag.Category").
+		Where("video_id = ?", videoID).
+		Order("id").
+		Find(&videoTags).Error
+	return videoTags, err
+}
+
+// AdjustUsageCounts adds the given deltas to tags.usage_count. Increments are
+// applied in place so that concurrent writers on other videos do not lose
+// updates, and in tag ID order so that they lock the rows in the same order
+// and cannot deadlock each other.
+func (r *TagRepo) AdjustUsageCounts(ctx context.Context, deltas map[int]int) error {
+	tagIDs := make([]int, 0, len(deltas))
+	for tagID, delta := range deltas {
+		if delta != 0 {
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+	sort.Ints(tagIDs)
+
+	for _, tagID := range tagIDs {
+		delta := deltas[tagID]
+		err := r.db.WithContext(ctx).Model(&tagModels.Tag{}).
+			Where("id = ?", tagID).
+			UpdateColumn("usage_count", gorm.Expr("GREATEST(usage_count + ?, 0)", delta)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package upload
+
+import (
+	"context"
+	"smart-scene-app-api/internal/models/upload"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	repositories.BaseRepository[upload.UploadSession]
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*upload.UploadSession, error)
+	CompareAndSetStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error)
+}
+
+type repository struct {
+	repositories.BaseRepository[upload.UploadSession]
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{
+		BaseRepository: repositories.NewBaseRepository[upload.UploadSession](db),
+		db:             db,
+	}
+}
+
+// GetByIDForUpdate locks the session row until the surrounding transaction ends.
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*upload.UploadSession, error) {
+	var session upload.UploadSession
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CompareAndSetStatus moves the session from one status to another and reports
+// whether this call won, so only one request can assemble an upload.
+func (r *repository) CompareAndSetStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&upload.UploadSession{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+type PartRepository interface {
+	repositories.BaseRepository[upload.UploadPart]
+	Upsert(ctx context.Context, part *upload.UploadPart) error
+	ListByUploadID(ctx context.Context, uploadID uuid.UUID) ([]upload.UploadPart, error)
+	DeletePart(ctx context.Context, uploadID uuid.UUID, partNumber int) error
+}
+
+type partRepository struct {
+	repositories.BaseRepository[upload.UploadPart]
+	db *gorm.DB
+}
+
+func NewPartRepository(db *gorm.DB) PartRepository {
+	return &partRepository{
+		BaseRepository: repositories.NewBaseRepository[upload.UploadPart](db),
+		db:             db,
+	}
+}
+
+// Upsert records a received part. Re-sending a part replaces the previous attempt.
+func (r *partRepository) Upsert(ctx context.Context, part *upload.UploadPart) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "upload_id"}, {Name: "part_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"size", "checksum", "created_at"}),
+		}).
+		Create(part).Error
+}
+
+func (r *partRepository) ListByUploadID(ctx context.Context, uploadID uuid.UUID) ([]upload.UploadPart, error) {
+	var parts []upload.UploadPart
+	err := r.db.WithContext(ctx).
+		Where("upload_id = ?", uploadID).
+		Order("part_number ASC").
+		Find(&parts).Error
+	return parts, err
+}
+
+// DeletePart forgets a received part, so that it is reported missing again.
+func (r *partRepository) DeletePart(ctx context.Context, uploadID uuid.UUID, partNumber int) error {
+	return r.db.WithContext(ctx).
+		Where("upload_id = ? AND part_number = ?", uploadID, partNumber).
+		Delete(&upload.UploadPart{}).Error
+}
@@ -2,17 +2,82 @@ package video
 
 import (
 	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
 	"smart-scene-app-api/internal/models/video"
 	"smart-scene-app-api/internal/repositories"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	repositories.BaseRepository[video.Video]
 	GetVideoTags(ctx context.Context, videoID uuid.UUID) ([]video.VideoTagInfo, error)
 	GetVideoTagsMap(ctx context.Context, videoIDs []uuid.UUID) (map[uuid.UUID][]video.VideoTagInfo, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*video.Video, error)
+	SoftDelete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	ListTrashed(ctx context.Context, params models.QueryParams, clauses ...repositories.Clause) ([]*video.Video, int64, error)
+	GetTrashedIDsBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	Purge(ctx context.Context, id uuid.UUID) (*video.Video, error)
+	GetTrashedByIDForUpdate(ctx context.Context, id uuid.UUID) (*video.Video, error)
+	ListByIDsWithTrashed(ctx context.Context, ids []uuid.UUID) ([]*video.Video, error)
+	GetSearchHits(ctx context.Context, videoIDs []uuid.UUID, q string) (map[uuid.UUID]video.VideoSearchHit, error)
+	GetFilterTags(ctx context.Context, tagIDs []int, tagCodes []string) ([]video.FilterTag, error)
+	GetFilterCategory(ctx context.Context, code string) (*video.FilterCategory, error)
+	GetRangeFilterTags(ctx context.Context, categoryID int, min, max *float64) ([]video.FilterTag, error)
+	CountByTag(ctx context.Context, onlyCategoryIDs, exceptCategoryIDs []int, clauses ...repositories.Clause) ([]video.TagFacetCount, error)
+	CountByCharacter(ctx context.Context, limit int, clauses ...repositories.Clause) ([]video.CharacterFacet, error)
+}
+
+const (
+	// SearchConfig is the text search configuration of videos.search_vector.
+	SearchConfig = "simple"
+
+	searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" ... \""
+)
+
+// VisibleTo filters the videos viewer can see: their own, organization-wide
+// ones and shared ones with a share applying to them.
+func VisibleTo(viewer common.Viewer) repositories.Clause {
+	return func(tx *gorm.DB) {
+		if viewer.Admin {
+			return
+		}
+		tx.Where(`(videos.created_by = @user
+			OR videos.visibility = @organization
+			OR (videos.visibility = @shared AND EXISTS (
+				SELECT 1 FROM video_shares vs
+				WHERE vs.video_id = videos.id AND (vs.user_id = @user OR vs.role_id = @role)
+			)))`, map[string]interface{}{
+			"user":         viewer.UserID,
+			"role":         viewer.RoleID,
+			"organization": video.VisibilityOrganization,
+			"shared":       video.VisibilityShared,
+		})
+	}
+}
+
+// SearchMatch filters videos whose search vector matches q, parsed as a web
+// search query.
+func SearchMatch(q string) repositories.Clause {
+	return func(tx *gorm.DB) {
+		tx.Where("videos.search_vector @@ websearch_to_tsquery(?, ?)", SearchConfig, q)
+	}
+}
+
+// SearchRankOrder orders videos by relevance to q, then by recency. It must be
+// the only order of the query: gorm drops expression orders when merging.
+func SearchRankOrder(q string) repositories.Clause {
+	return func(tx *gorm.DB) {
+		tx.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank_cd(videos.search_vector, websearch_to_tsquery(?, ?)) DESC, videos.created_at DESC",
+			Vars: []interface{}{SearchConfig, q},
+		}})
+	}
 }
 
 type repository struct {
@@ -90,3 +155,310 @@ func (r *repository) GetVideoTagsMap(ctx context.Context, videoIDs []uuid.UUID)
 
 	return tagsMap, nil
 }
+
+// GetByIDForUpdate locks the video row until the surrounding transaction ends.
+// It must be called on a repository built from a transaction handle.
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*video.Video, error) {
+	var v video.Video
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&v, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// SoftDelete moves a video to the trash. It returns gorm.ErrRecordNotFound when
+// the video does not exist or is already trashed.
+func (r *repository) SoftDelete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&video.Video{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"deleted_at": time.Now(),
+			"deleted_by": deletedBy,
+			"updated_by": deletedBy,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Restore takes a video out of the trash. It returns gorm.ErrRecordNotFound
+// when no trashed video has this ID.
+func (r *repository) Restore(ctx context.Context, id uuid.UUID) error {
+	res := r.db.WithContext(ctx).Unscoped().Model(&video.Video{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) ListTrashed(ctx context.Context, params models.QueryParams, clauses ...repositories.Clause) ([]*video.Video, int64, error) {
+	tx := r.db.WithContext(ctx).Unscoped().Model(&video.Video{}).Where("deleted_at IS NOT NULL")
+	for _, f := range clauses {
+		f(tx)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var videos []*video.Video
+	query := tx.Order("deleted_at DESC").Offset(params.Offset)
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if err := query.Find(&videos).Error; err != nil {
+		return nil, 0, err
+	}
+	return videos, total, nil
+}
+
+// GetTrashedByIDForUpdate locks a trashed video until the surrounding
+// transaction ends, or returns gorm.ErrRecordNotFound. It must be called on a
+// repository built from a transaction handle.
+func (r *repository) GetTrashedByIDForUpdate(ctx context.Context, id uuid.UUID) (*video.Video, error) {
+	var v video.Video
+	err := r.db.WithContext(ctx).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Take(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListByIDsWithTrashed returns the videos with the given IDs, trashed ones
+// included.
+func (r *repository) ListByIDsWithTrashed(ctx context.Context, ids []uuid.UUID) ([]*video.Video, error) {
+	var videos []*video.Video
+	if len(ids) == 0 {
+		return videos, nil
+	}
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id IN ?", ids).
+		Find(&videos).Error
+	return videos, err
+}
+
+func (r *repository) GetTrashedIDsBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Unscoped().Model(&video.Video{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Purge hard-deletes a trashed video together with every row that references
+// it. It must be called on a repository built from a transaction handle so
+// that the video and its dependents go away together.
+func (r *repository) Purge(ctx context.Context, id uuid.UUID) (*video.Video, error) {
+	db := r.db.WithContext(ctx)
+
+	var v video.Video
+	err := db.Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NOT NULL").
+		First(&v, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{
+		`UPDATE ` + common.POSTGRES_TABLE_NAME_TAGS + ` t SET usage_count = GREATEST(t.usage_count - vt.cnt, 0)
+		FROM (SELECT tag_id, COUNT(*) AS cnt FROM ` + common.POSTGRES_TABLE_NAME_VIDEO_TAGS + ` WHERE video_id = ? GROUP BY tag_id) vt
+		WHERE t.id = vt.tag_id`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_VIDEO_TAGS + ` WHERE video_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES + ` WHERE video_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_VIDEO_STATUS_HISTORIES + ` WHERE video_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_VIDEO_SHARES + ` WHERE video_id = ?`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_UPLOAD_PARTS + ` WHERE upload_id IN (SELECT id FROM ` + common.POSTGRES_TABLE_NAME_UPLOAD_SESSIONS + ` WHERE video_id = ?)`,
+		`DELETE FROM ` + common.POSTGRES_TABLE_NAME_UPLOAD_SESSIONS + ` WHERE video_id = ?`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt, id).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.Unscoped().Delete(&video.Video{}, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetSearchHits computes the rank and highlighted snippets of the given videos
+// for q. Headlines are costly, so they are only built for a page of results.
+func (r *repository) GetSearchHits(ctx context.Context, videoIDs []uuid.UUID, q string) (map[uuid.UUID]video.VideoSearchHit, error) {
+	hits := make(map[uuid.UUID]video.VideoSearchHit, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return hits, nil
+	}
+
+	var results []struct {
+		ID uuid.UUID
+		video.VideoSearchHit
+	}
+
+	query := `
+		SELECT
+			v.id,
+			ts_rank_cd(v.search_vector, q.query) AS rank,
+			ts_headline(@config, v.title, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+			ts_headline(@config, COALESCE(v.search_document, v.title), q.query, @options) AS snippet
+		FROM videos v, websearch_to_tsquery(@config, @q) AS q(query)
+		WHERE v.id IN @ids
+	`
+
+	err := r.db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"config":  SearchConfig,
+		"q":       q,
+		"options": searchHeadlineOptions,
+		"ids":     videoIDs,
+	}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		hits[result.ID] = result.VideoSearchHit
+	}
+	return hits, nil
+}
+
+// GetFilterTags resolves the active tags selected by ID or code in a listing
+// filter.
+func (r *repository) GetFilterTags(ctx context.Context, tagIDs []int, tagCodes []string) ([]video.FilterTag, error) {
+	var tags []video.FilterTag
+	if len(tagIDs) == 0 && len(tagCodes) == 0 {
+		return tags, nil
+	}
+
+	tx := r.db.WithContext(ctx).
+		Table("tags t").
+		Select("t.id AS tag_id, t.category_id, tc.filter_type").
+		Joins("JOIN tag_categories tc ON t.category_id = tc.id").
+		Where("t.is_active = ?", true)
+
+	switch {
+	case len(tagIDs) > 0 && len(tagCodes) > 0:
+		tx = tx.Where("t.id IN ? OR t.code IN ?", tagIDs, tagCodes)
+	case len(tagIDs) > 0:
+		tx = tx.Where("t.id IN ?", tagIDs)
+	default:
+		tx = tx.Where("t.code IN ?", tagCodes)
+	}
+
+	err := tx.Order("t.id").Scan(&tags).Error
+	return tags, err
+}
+
+// GetFilterCategory returns the tag category with the given code, or
+// gorm.ErrRecordNotFound.
+func (r *repository) GetFilterCategory(ctx context.Context, code string) (*video.FilterCategory, error) {
+	var category video.FilterCategory
+	err := r.db.WithContext(ctx).
+		Table("tag_categories").
+		Select("id, code, filter_type").
+		Where("code = ?", code).
+		Take(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetRangeFilterTags returns the active tags of a range category whose bounds
+// overlap [min, max]. A nil bound is open.
+func (r *repository) GetRangeFilterTags(ctx context.Context, categoryID int, min, max *float64) ([]video.FilterTag, error) {
+	var tags []video.FilterTag
+
+	tx := r.db.WithContext(ctx).
+		Table("tags t").
+		Select("t.id AS tag_id, t.category_id, tc.filter_type").
+		Joins("JOIN tag_categories tc ON t.category_id = tc.id").
+		Where("t.category_id = ? AND t.is_active = ?", categoryID, true).
+		Where("t.range_min IS NOT NULL OR t.range_max IS NOT NULL")
+
+	if min != nil {
+		tx = tx.Where("t.range_max IS NULL OR t.range_max >= ?", *min)
+	}
+	if max != nil {
+		tx = tx.Where("t.range_min IS NULL OR t.range_min <= ?", *max)
+	}
+
+	err := tx.Order("t.id").Scan(&tags).Error
+	return tags, err
+}
+
+// filteredIDs is a subquery selecting the IDs of the videos matching clauses.
+func (r *repository) filteredIDs(ctx context.Context, clauses []repositories.Clause) *gorm.DB {
+	tx := r.db.WithContext(ctx).Model(&video.Video{}).Select("videos.id")
+	for _, f := range clauses {
+		f(tx)
+	}
+	return tx
+}
+
+// CountByTag counts the videos matching clauses per active tag. The tags can be
+// restricted to some categories or exclude others.
+func (r *repository) CountByTag(ctx context.Context, onlyCategoryIDs, exceptCategoryIDs []int, clauses ...repositories.Clause) ([]video.TagFacetCount, error) {
+	var counts []video.TagFacetCount
+
+	tx := r.db.WithContext(ctx).
+		Table("video_tags vt").
+		Select(`t.id AS tag_id, t.name AS tag_name, t.code AS tag_code,
+			tc.id AS category_id, tc.name AS category_name, tc.code AS category_code,
+			tc.priority AS category_priority, tc.filter_type,
+			COUNT(DISTINCT vt.video_id) AS count`).
+		Joins("JOIN tags t ON vt.tag_id = t.id").
+		Joins("JOIN tag_categories tc ON t.category_id = tc.id").
+		Where("t.is_active = ?", true).
+		Where("vt.video_id IN (?)", r.filteredIDs(ctx, clauses))
+
+	if len(onlyCategoryIDs) > 0 {
+		tx = tx.Where("tc.id IN ?", onlyCategoryIDs)
+	}
+	if len(exceptCategoryIDs) > 0 {
+		tx = tx.Where("tc.id NOT IN ?", exceptCategoryIDs)
+	}
+
+	err := tx.Group("t.id, tc.id").
+		Order("tc.priority ASC, t.sort_order ASC, t.id ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountByCharacter counts the videos matching clauses per character, most
+// frequent first.
+func (r *repository) CountByCharacter(ctx context.Context, limit int, clauses ...repositories.Clause) ([]video.CharacterFacet, error) {
+	var counts []video.CharacterFacet
+
+	err := r.db.WithContext(ctx).
+		Table("character_appearances ca").
+		Select("c.id AS character_id, c.name, COUNT(DISTINCT ca.video_id) AS count").
+		Joins("JOIN characters c ON ca.character_id = c.id").
+		Where("ca.video_id IN (?) AND NOT ca.is_rejected", r.filteredIDs(ctx, clauses)).
+		Group("c.id").
+		Order("count DESC, c.name ASC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
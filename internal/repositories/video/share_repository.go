@@ -0,0 +1,65 @@
+package video
+
+import (
+	"context"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareRepository interface {
+	repositories.BaseRepository[video.VideoShare]
+	ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]video.VideoShare, error)
+	ListGrants(ctx context.Context, videoID uuid.UUID, viewer common.Viewer) ([]video.VideoShare, error)
+	FindByGrantee(ctx context.Context, videoID uuid.UUID, userID, roleID *uuid.UUID) (*video.VideoShare, error)
+}
+
+type shareRepository struct {
+	repositories.BaseRepository[video.VideoShare]
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) ShareRepository {
+	return &shareRepository{
+		BaseRepository: repositories.NewBaseRepository[video.VideoShare](db),
+		db:             db,
+	}
+}
+
+func (r *shareRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]video.VideoShare, error) {
+	var shares []video.VideoShare
+	err := r.db.WithContext(ctx).
+		Where("video_id = ?", videoID).
+		Order("created_at ASC, id ASC").
+		Find(&shares).Error
+	return shares, err
+}
+
+// ListGrants returns the shares of a video that apply to viewer, directly or
+// through their role.
+func (r *shareRepository) ListGrants(ctx context.Context, videoID uuid.UUID, viewer common.Viewer) ([]video.VideoShare, error) {
+	var shares []video.VideoShare
+	err := r.db.WithContext(ctx).
+		Where("video_id = ? AND (user_id = ? OR role_id = ?)", videoID, viewer.UserID, viewer.RoleID).
+		Find(&shares).Error
+	return shares, err
+}
+
+// FindByGrantee returns the share of a video with a user or a role, or
+// gorm.ErrRecordNotFound.
+func (r *shareRepository) FindByGrantee(ctx context.Context, videoID uuid.UUID, userID, roleID *uuid.UUID) (*video.VideoShare, error) {
+	var share video.VideoShare
+	tx := r.db.WithContext(ctx).Where("video_id = ?", videoID)
+	if userID != nil {
+		tx = tx.Where("user_id = ?", *userID)
+	} else {
+		tx = tx.Where("role_id = ?", roleID)
+	}
+	if err := tx.Take(&share).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
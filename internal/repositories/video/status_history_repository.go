@@ -0,0 +1,36 @@
+package video
+
+import (
+	"context"
+	"smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StatusHistoryRepository interface {
+	repositories.BaseRepository[video.VideoStatusHistory]
+	ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]video.VideoStatusHistory, error)
+}
+
+type statusHistoryRepository struct {
+	repositories.BaseRepository[video.VideoStatusHistory]
+	db *gorm.DB
+}
+
+func NewStatusHistoryRepository(db *gorm.DB) StatusHistoryRepository {
+	return &statusHistoryRepository{
+		BaseRepository: repositories.NewBaseRepository[video.VideoStatusHistory](db),
+		db:             db,
+	}
+}
+
+func (r *statusHistoryRepository) ListByVideoID(ctx context.Context, videoID uuid.UUID) ([]video.VideoStatusHistory, error) {
+	var histories []video.VideoStatusHistory
+	err := r.db.WithContext(ctx).
+		Where("video_id = ?", videoID).
+		Order("created_at ASC, id ASC").
+		Find(&histories).Error
+	return histories, err
+}
@@ -3,8 +3,11 @@ package services
 import (
 	"smart-scene-app-api/internal/services/auth"
 	"smart-scene-app-api/internal/services/character"
+	"smart-scene-app-api/internal/services/collection"
 	"smart-scene-app-api/internal/services/tag"
+	"smart-scene-app-api/internal/services/upload"
 	"smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/internal/services/webhook"
 	l "smart-scene-app-api/pkg/logger"
 	"smart-scene-app-api/server"
 
@@ -12,11 +15,14 @@ import (
 )
 
 type Service struct {
-	Auth      auth.Service
-	Video     video.Service
-	Character character.Service
-	Tag       tag.Service
-	logger    *zap.Logger
+	Auth       auth.Service
+	Video      video.Service
+	Character  character.Service
+	Tag        tag.Service
+	Upload     upload.Service
+	Webhook    webhook.Service
+	Collection collection.Service
+	logger     *zap.Logger
 }
 
 // Services alias for consistency
@@ -32,12 +38,18 @@ func NewServices(sc server.ServerContext) *Services {
 	videoService := video.NewVideoService(sc)
 	characterService := character.NewCharacterService(sc)
 	tagService := tag.NewTagService(sc)
+	uploadService := upload.NewUploadService(sc)
+	webhookService := webhook.NewWebhookService(sc)
+	collectionService := collection.NewCollectionService(sc)
 
 	return &Services{
-		logger:    l.New(),
-		Auth:      authService,
-		Video:     videoService,
-		Character: characterService,
-		Tag:       tagService,
+		logger:     l.New(),
+		Auth:       authService,
+		Video:      videoService,
+		Character:  characterService,
+		Tag:        tagService,
+		Upload:     uploadService,
+		Webhook:    webhookService,
+		Collection: collectionService,
 	}
 }
@@ -0,0 +1,452 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+	characterRepo "smart-scene-app-api/internal/repositories/character"
+	videoService "smart-scene-app-api/internal/services/video"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetVideoAppearances lists the appearances of a video, rejected ones
+// included, so that reviewers can edit them.
+func (s *characterService) GetVideoAppearances(videoID string, queryParams characterModel.VideoAppearanceFilterAndPagination, viewer common.Viewer) (*characterModel.AppearanceListResponse, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if _, err := videoService.AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer); err != nil {
+		return nil, err
+	}
+
+	clauses := []repositories.Clause{func(tx *gorm.DB) {
+		tx.Where("video_id = ?", uuidID)
+	}}
+	if queryParams.CharacterID != "" {
+		characterID, err := uuid.Parse(queryParams.CharacterID)
+		if err != nil {
+			return nil, common.ErrInvalidUUID
+		}
+		clauses = append(clauses, func(tx *gorm.DB) {
+			tx.Where("character_id = ?", characterID)
+		})
+	}
+	switch queryParams.ReviewStatus {
+	case characterModel.ReviewStatusPending:
+		clauses = append(clauses, func(tx *gorm.DB) {
+			tx.Where("NOT is_confirmed AND NOT is_rejected")
+		})
+	case characterModel.ReviewStatusConfirmed:
+		clauses = append(clauses, func(tx *gorm.DB) {
+			tx.Where("is_confirmed")
+		})
+	case characterModel.ReviewStatusRejected:
+		clauses = append(clauses, func(tx *gorm.DB) {
+			tx.Where("is_rejected")
+		})
+	}
+	combinedFilter := func(tx *gorm.DB) {
+		for _, clause := range clauses {
+			clause(tx)
+		}
+	}
+
+	queryParams.VerifyPaging()
+	total, err := s.appearanceRepo.Count(s.sc.Ctx(), models.QueryParams{}, combinedFilter)
+	if err != nil {
+		return nil, err
+	}
+	appearances, err := s.appearanceRepo.List(s.sc.Ctx(), models.QueryParams{
+		Limit:  queryParams.PageSize,
+		Offset: (queryParams.Page - 1) * queryParams.PageSize,
+	}, combinedFilter, func(tx *gorm.DB) {
+		tx.Order("start_time ASC, id ASC")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]characterModel.CharacterAppearance, 0, len(appearances))
+	for _, a := range appearances {
+		items = append(items, *a)
+	}
+	return &characterModel.AppearanceListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    int(total),
+			Page:     queryParams.Page,
+			PageSize: queryParams.PageSize,
+		},
+		Items: items,
+	}, nil
+}
+
+// CreateAppearance adds an appearance by hand. It is created confirmed, so
+// that ingestions keep it.
+func (s *characterService) CreateAppearance(videoID string, req characterModel.CreateAppearanceRequest, ifMatch int, viewer common.Viewer) (*characterModel.CharacterAppearance, error) {
+	if err := s.ensureActiveCharacters([]uuid.UUID{req.CharacterID}); err != nil {
+		return nil, err
+	}
+
+	confidence := 1.0
+	if req.Confidence != nil {
+		confidence = *req.Confidence
+	}
+	now := time.Now()
+	appearance := &characterModel.CharacterAppearance{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		CreatedBy:   viewer.UserID,
+		CharacterID: req.CharacterID,
+		Confidence:  confidence,
+		Metadata:    req.Metadata,
+		IsConfirmed: true,
+		ReviewedBy:  &viewer.UserID,
+		ReviewedAt:  &now,
+	}
+	err := s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		appearance.VideoID = video.ID
+		if err := setAppearanceSpan(video, appearance, req.StartTime, req.EndTime, videoFPS(video, req.FPS)); err != nil {
+			return err
+		}
+		_, err := characterRepo.NewAppearanceRepository(tx).Create(ctx, appearance)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appearance, nil
+}
+
+// UpdateAppearance trims, extends or reassigns an appearance. An edited
+// appearance counts as reviewed and is marked confirmed.
+func (s *characterService) UpdateAppearance(videoID, appearanceID string, req characterModel.UpdateAppearanceRequest, ifMatch int, viewer common.Viewer) (*characterModel.CharacterAppearance, error) {
+	uuidID, err := uuid.Parse(appearanceID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if req.CharacterID != nil {
+		if err := s.ensureActiveCharacters([]uuid.UUID{*req.CharacterID}); err != nil {
+			return nil, err
+		}
+	}
+
+	var appearance *characterModel.CharacterAppearance
+	err = s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		repo := characterRepo.NewAppearanceRepository(tx)
+		appearance, err = lockAppearance(ctx, repo, video.ID, uuidID)
+		if err != nil {
+			return err
+		}
+
+		if req.StartTime != nil || req.EndTime != nil {
+			start, end := appearance.StartTime, appearance.EndTime
+			if req.StartTime != nil {
+				start = *req.StartTime
+			}
+			if req.EndTime != nil {
+				end = *req.EndTime
+			}
+			if err := setAppearanceSpan(video, appearance, start, end, appearanceFPS(video, req.FPS, appearance)); err != nil {
+				return err
+			}
+		}
+		if req.CharacterID != nil {
+			appearance.CharacterID = *req.CharacterID
+		}
+		if req.Confidence != nil {
+			appearance.Confidence = *req.Confidence
+		}
+		if req.Metadata != nil {
+			appearance.Metadata = req.Metadata
+		}
+		markReviewed(appearance, characterModel.ReviewStatusConfirmed, viewer)
+		return saveAppearance(ctx, repo, appearance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appearance, nil
+}
+
+// SplitAppearance cuts an appearance in two at a time strictly inside it. Both
+// halves keep its character, confidence and metadata and are marked
+// confirmed.
+func (s *characterService) SplitAppearance(videoID, appearanceID string, req characterModel.SplitAppearanceRequest, ifMatch int, viewer common.Viewer) (*characterModel.AppearanceListResponse, error) {
+	uuidID, err := uuid.Parse(appearanceID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	var first, second *characterModel.CharacterAppearance
+	err = s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		repo := characterRepo.NewAppearanceRepository(tx)
+		first, err = lockAppearance(ctx, repo, video.ID, uuidID)
+		if err != nil {
+			return err
+		}
+		if first.IsRejected {
+			return fmt.Errorf("%w: appearance %s is rejected", common.ErrInvalidAppearance, first.ID)
+		}
+		if req.At <= first.StartTime || req.At >= first.EndTime {
+			return fmt.Errorf("%w: split time %.3f is not inside %.3f-%.3f", common.ErrInvalidAppearance, req.At, first.StartTime, first.EndTime)
+		}
+
+		fps := appearanceFPS(video, req.FPS, first)
+		end := first.EndTime
+		copied := *first
+		second = &copied
+		second.ID = uuid.New()
+		second.CreatedAt = time.Now()
+		second.CreatedBy = viewer.UserID
+		if err := setAppearanceSpan(video, first, first.StartTime, req.At, fps); err != nil {
+			return err
+		}
+		if err := setAppearanceSpan(video, second, req.At, end, fps); err != nil {
+			return err
+		}
+		markReviewed(first, characterModel.ReviewStatusConfirmed, viewer)
+		markReviewed(second, characterModel.ReviewStatusConfirmed, viewer)
+
+		if err := saveAppearance(ctx, repo, first); err != nil {
+			return err
+		}
+		_, err = repo.Create(ctx, second)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &characterModel.AppearanceListResponse{
+		BaseListResponse: models.BaseListResponse{Total: 2},
+		Items:            []characterModel.CharacterAppearance{*first, *second},
+	}, nil
+}
+
+// MergeAppearances joins appearances of the same character into the earliest
+// one, which is extended to span all of them and marked confirmed; the others
+// are deleted. Frames are taken from the merged appearances, and the
+// confidence is their duration-weighted average.
+func (s *characterService) MergeAppearances(videoID string, req characterModel.MergeAppearancesRequest, ifMatch int, viewer common.Viewer) (*characterModel.CharacterAppearance, error) {
+	var merged *characterModel.CharacterAppearance
+	err := s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		repo := characterRepo.NewAppearanceRepository(tx)
+
+		var appearances []*characterModel.CharacterAppearance
+		seen := make(map[uuid.UUID]bool, len(req.AppearanceIDs))
+		for _, id := range req.AppearanceIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			a, err := lockAppearance(ctx, repo, video.ID, id)
+			if err != nil {
+				return err
+			}
+			if a.IsRejected {
+				return fmt.Errorf("%w: appearance %s is rejected", common.ErrInvalidAppearance, a.ID)
+			}
+			if len(appearances) > 0 && a.CharacterID != appearances[0].CharacterID {
+				return fmt.Errorf("%w: merged appearances must belong to the same character", common.ErrInvalidAppearance)
+			}
+			appearances = append(appearances, a)
+		}
+		if len(appearances) < 2 {
+			return fmt.Errorf("%w: a merge needs at least two distinct appearances", common.ErrInvalidAppearance)
+		}
+
+		merged = appearances[0]
+		for _, a := range appearances[1:] {
+			if a.StartTime < merged.StartTime {
+				merged = a
+			}
+		}
+		var removed []uuid.UUID
+		var weighted, total, maxConfidence float64
+		for _, a := range appearances {
+			if a != merged {
+				removed = append(removed, a.ID)
+			}
+			merged.StartFrame = min(merged.StartFrame, a.StartFrame)
+			merged.EndFrame = max(merged.EndFrame, a.EndFrame)
+			merged.EndTime = math.Max(merged.EndTime, a.EndTime)
+			weighted += a.Confidence * a.Duration
+			total += a.Duration
+			maxConfidence = math.Max(maxConfidence, a.Confidence)
+		}
+		merged.Duration = merged.EndTime - merged.StartTime
+		merged.Confidence = maxConfidence
+		if total > 0 {
+			merged.Confidence = math.Round(weighted/total*10000) / 10000
+		}
+		markReviewed(merged, characterModel.ReviewStatusConfirmed, viewer)
+
+		if err := saveAppearance(ctx, repo, merged); err != nil {
+			return err
+		}
+		return repo.Delete(ctx, func(tx *gorm.DB) {
+			tx.Where("id IN ?", removed)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// DeleteAppearance deletes an appearance. An automatic detection deleted this
+// way comes back with the next ingestion; rejecting it prevents that.
+func (s *characterService) DeleteAppearance(videoID, appearanceID string, ifMatch int, viewer common.Viewer) error {
+	uuidID, err := uuid.Parse(appearanceID)
+	if err != nil {
+		return common.ErrInvalidUUID
+	}
+	return s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		repo := characterRepo.NewAppearanceRepository(tx)
+		if _, err := lockAppearance(ctx, repo, video.ID, uuidID); err != nil {
+			return err
+		}
+		return repo.Delete(ctx, func(tx *gorm.DB) {
+			tx.Where("id = ?", uuidID)
+		})
+	})
+}
+
+// ReviewAppearance confirms or rejects an appearance, or returns it to
+// pending so that the next ingestion replaces it.
+func (s *characterService) ReviewAppearance(videoID, appearanceID string, req characterModel.ReviewAppearanceRequest, ifMatch int, viewer common.Viewer) (*characterModel.CharacterAppearance, error) {
+	uuidID, err := uuid.Parse(appearanceID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	var appearance *characterModel.CharacterAppearance
+	err = s.editAppearances(videoID, ifMatch, viewer, func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error {
+		repo := characterRepo.NewAppearanceRepository(tx)
+		appearance, err = lockAppearance(ctx, repo, video.ID, uuidID)
+		if err != nil {
+			return err
+		}
+		markReviewed(appearance, req.Status, viewer)
+		return saveAppearance(ctx, repo, appearance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appearance, nil
+}
+
+// editAppearances runs edit in a transaction holding the video row lock, after
+// checking that viewer can edit the video and, when ifMatch is set, that it is
+// still at that version. The character count of the video is then recomputed
+// and its version incremented.
+func (s *characterService) editAppearances(videoID string, ifMatch int, viewer common.Viewer, edit func(ctx context.Context, tx *gorm.DB, video *videoModel.Video) error) error {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return common.ErrInvalidUUID
+	}
+
+	return s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		video, err := videoService.LockVideo(ctx, tx, uuidID, viewer, videoModel.AccessEditor, ifMatch)
+		if err != nil {
+			return err
+		}
+		if err := edit(ctx, tx, video); err != nil {
+			return err
+		}
+		return characterRepo.NewRepository(tx).RecountVideoCharacters(ctx, []uuid.UUID{uuidID})
+	})
+}
+
+func lockAppearance(ctx context.Context, repo characterRepo.AppearanceRepository, videoID, id uuid.UUID) (*characterModel.CharacterAppearance, error) {
+	a, err := repo.GetByIDForUpdate(ctx, videoID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrAppearanceNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// saveAppearance writes the editable columns of an appearance, zero values
+// included.
+func saveAppearance(ctx context.Context, repo characterRepo.AppearanceRepository, a *characterModel.CharacterAppearance) error {
+	_, err := repo.UpdateColumns(ctx, a.ID, map[string]interface{}{
+		"character_id": a.CharacterID,
+		"start_frame":  a.StartFrame,
+		"end_frame":    a.EndFrame,
+		"start_time":   a.StartTime,
+		"end_time":     a.EndTime,
+		"duration":     a.Duration,
+		"confidence":   a.Confidence,
+		"metadata":     a.Metadata,
+		"is_confirmed": a.IsConfirmed,
+		"is_rejected":  a.IsRejected,
+		"reviewed_by":  a.ReviewedBy,
+		"reviewed_at":  a.ReviewedAt,
+	})
+	return err
+}
+
+// markReviewed sets the review status of an appearance and records who set
+// it; pending clears the record.
+func markReviewed(a *characterModel.CharacterAppearance, status string, viewer common.Viewer) {
+	a.IsConfirmed = status == characterModel.ReviewStatusConfirmed
+	a.IsRejected = status == characterModel.ReviewStatusRejected
+	if status == characterModel.ReviewStatusPending {
+		a.ReviewedBy, a.ReviewedAt = nil, nil
+		return
+	}
+	now := time.Now()
+	reviewer := viewer.UserID
+	a.ReviewedBy, a.ReviewedAt = &reviewer, &now
+}
+
+// appearanceFPS is the frame rate used to derive the frames of an edited
+// appearance: the requested one, metadata.fps of the video, or else the rate
+// implied by the current frames and times of the appearance.
+func appearanceFPS(video *videoModel.Video, requested float64, a *characterModel.CharacterAppearance) float64 {
+	if fps := videoFPS(video, requested); fps > 0 {
+		return fps
+	}
+	if a.EndTime > a.StartTime && a.EndFrame > a.StartFrame {
+		return float64(a.EndFrame-a.StartFrame) / (a.EndTime - a.StartTime)
+	}
+	return 0
+}
+
+// setAppearanceSpan sets the times of an appearance after checking them
+// against the video, and derives its duration and the frames of the moved
+// boundaries from them.
+func setAppearanceSpan(video *videoModel.Video, a *characterModel.CharacterAppearance, start, end, fps float64) error {
+	moved := start != a.StartTime || end != a.EndTime
+	switch {
+	case end < start:
+		return fmt.Errorf("%w: end_time is before start_time", common.ErrInvalidAppearance)
+	case end > maxVideoTime(video):
+		return fmt.Errorf("%w: end_time %.3f exceeds video duration %d", common.ErrInvalidAppearance, end, video.Duration)
+	case moved && fps <= 0:
+		return fmt.Errorf("%w: the frame rate of the video is unknown; pass fps", common.ErrInvalidAppearance)
+	}
+	if start != a.StartTime {
+		a.StartFrame = int(math.Round(start * fps))
+	}
+	if end != a.EndTime {
+		a.EndFrame = int(math.Round(end * fps))
+	}
+	a.StartTime = start
+	a.EndTime = end
+	a.Duration = end - start
+	return nil
+}
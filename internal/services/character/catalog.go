@@ -0,0 +1,302 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	"smart-scene-app-api/internal/repositories"
+	characterRepo "smart-scene-app-api/internal/repositories/character"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCharacters lists the character catalog. Characters are shared by the
+// whole library, so every authenticated user sees all of them.
+func (s *characterService) GetCharacters(queryParams characterModel.CharacterFilterAndPagination, viewer common.Viewer) (*characterModel.CharacterListResponse, error) {
+	queryParams.VerifyPaging()
+
+	var filters []repositories.Clause
+	if queryParams.Name != "" {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("name ILIKE ?", "%"+queryParams.Name+"%")
+		})
+	}
+	if queryParams.IsActive != nil {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("is_active = ?", *queryParams.IsActive)
+		})
+	}
+	if queryParams.CreatedBy != uuid.Nil {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("created_by = ?", queryParams.CreatedBy)
+		})
+	}
+
+	sort := queryParams.Sort
+	if sort == "" {
+		sort = "name.asc"
+	}
+	// Checked up front so that an invalid sort is reported even when no
+	// character matches.
+	if _, err := (models.QuerySort{Origin: sort}).Parse(characterModel.Character{}.SortColumns()); err != nil {
+		return nil, err
+	}
+
+	total, err := s.characterRepo.Count(s.sc.Ctx(), models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &characterModel.CharacterListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    int(total),
+			Page:     queryParams.Page,
+			PageSize: queryParams.PageSize,
+		},
+		Items: []characterModel.CharacterResponse{},
+	}
+	if total == 0 {
+		return response, nil
+	}
+
+	characters, err := s.characterRepo.List(s.sc.Ctx(), models.QueryParams{
+		Limit:     queryParams.PageSize,
+		Offset:    (queryParams.Page - 1) * queryParams.PageSize,
+		QuerySort: models.QuerySort{Origin: sort},
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(characters))
+	for _, c := range characters {
+		ids = append(ids, c.ID)
+	}
+	usage, err := s.characterRepo.CountUsage(s.sc.Ctx(), ids, videoRepo.VisibleTo(viewer))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range characters {
+		response.Items = append(response.Items, characterModel.CharacterResponse{
+			Character:      *c,
+			CharacterUsage: usage[c.ID],
+		})
+	}
+	return response, nil
+}
+
+func (s *characterService) GetCharacter(id string, viewer common.Viewer) (*characterModel.CharacterResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	c, err := s.characterRepo.GetByID(s.sc.Ctx(), uuidID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	return s.withUsage(s.characterRepo, c, viewer)
+}
+
+func (s *characterService) CreateCharacter(req characterModel.CreateCharacterRequest, viewer common.Viewer) (*characterModel.CharacterResponse, error) {
+	exists, err := s.characterRepo.ExistsByName(s.sc.Ctx(), req.Name, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, common.ErrCharacterAlreadyExists
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	created, err := s.characterRepo.Create(s.sc.Ctx(), &characterModel.Character{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Avatar:      req.Avatar,
+		Metadata:    req.Metadata,
+		IsActive:    isActive,
+		CreatedBy:   viewer.UserID,
+		UpdatedBy:   viewer.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// is_active defaults to true in the database, so false is not written by
+	// the insert above.
+	if !isActive {
+		if created, err = s.characterRepo.UpdateColumns(s.sc.Ctx(), created.ID, map[string]interface{}{"is_active": false}); err != nil {
+			return nil, err
+		}
+	}
+	return &characterModel.CharacterResponse{Character: *created}, nil
+}
+
+// UpdateCharacter changes the fields set in req. Only admins and the creator
+// of the character may update it. A non-zero ifMatch makes the update
+// conditional on the current version of the character.
+func (s *characterService) UpdateCharacter(id string, req characterModel.UpdateCharacterRequest, ifMatch int, viewer common.Viewer) (*characterModel.CharacterResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if req.Name != nil && *req.Name == "" {
+		return nil, common.ErrInvalidCharacter
+	}
+
+	var response *characterModel.CharacterResponse
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		repo := characterRepo.NewRepository(tx)
+		c, err := lockCharacter(s.sc.Ctx(), tx, uuidID, ifMatch)
+		if err != nil {
+			return err
+		}
+		if !canEditCharacter(c, viewer) {
+			return common.ErrCharacterForbidden
+		}
+
+		columns := map[string]interface{}{"updated_by": viewer.UserID}
+		if req.Name != nil {
+			exists, err := repo.ExistsByName(s.sc.Ctx(), *req.Name, uuidID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return common.ErrCharacterAlreadyExists
+			}
+			columns["name"] = *req.Name
+		}
+		if req.Description != nil {
+			columns["description"] = *req.Description
+		}
+		if req.Avatar != nil {
+			columns["avatar"] = *req.Avatar
+		}
+		if req.Metadata != nil {
+			columns["metadata"] = req.Metadata
+		}
+		if req.IsActive != nil {
+			columns["is_active"] = *req.IsActive
+		}
+
+		updated, err := repo.UpdateColumns(s.sc.Ctx(), uuidID, columns)
+		if err != nil {
+			return err
+		}
+		response, err = s.withUsage(repo, updated, viewer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeleteCharacter deletes a character that no appearance, rejected ones
+// included, and no video tag refers to. Only admins and the creator of the
+// character may delete it. With force, its appearances and video tag
+// attachments are deleted too; only admins can force, since this edits videos
+// they may not otherwise reach.
+func (s *characterService) DeleteCharacter(id string, force bool, ifMatch int, viewer common.Viewer) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return common.ErrInvalidUUID
+	}
+	if force && !viewer.Admin {
+		return common.ErrCharacterForbidden
+	}
+
+	return s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		repo := characterRepo.NewRepository(tx)
+		c, err := lockCharacter(s.sc.Ctx(), tx, uuidID, ifMatch)
+		if err != nil {
+			return err
+		}
+		if !canEditCharacter(c, viewer) {
+			return common.ErrCharacterForbidden
+		}
+		if !force {
+			referenced, err := repo.IsReferenced(s.sc.Ctx(), uuidID)
+			if err != nil {
+				return err
+			}
+			if referenced {
+				return common.ErrCharacterInUse
+			}
+		}
+		return repo.DeleteWithUsage(s.sc.Ctx(), uuidID)
+	})
+}
+
+// GetCharacterVideos lists the videos visible to viewer that a character
+// appears in.
+func (s *characterService) GetCharacterVideos(id string, queryParams characterModel.CharacterVideoFilterAndPagination, viewer common.Viewer) (*characterModel.CharacterVideoListResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if _, err := s.characterRepo.GetByID(s.sc.Ctx(), uuidID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCharacterNotFound
+		}
+		return nil, err
+	}
+
+	queryParams.VerifyPaging()
+	usage, total, err := s.characterRepo.ListVideoUsage(s.sc.Ctx(), uuidID,
+		queryParams.PageSize, (queryParams.Page-1)*queryParams.PageSize,
+		videoRepo.VisibleTo(viewer))
+	if err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		usage = []characterModel.CharacterVideoUsage{}
+	}
+
+	return &characterModel.CharacterVideoListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    int(total),
+			Page:     queryParams.Page,
+			PageSize: queryParams.PageSize,
+		},
+		Items: usage,
+	}, nil
+}
+
+// withUsage adds to c its usage in the videos visible to viewer.
+func (s *characterService) withUsage(repo characterRepo.Repository, c *characterModel.Character, viewer common.Viewer) (*characterModel.CharacterResponse, error) {
+	usage, err := repo.CountUsage(s.sc.Ctx(), []uuid.UUID{c.ID}, videoRepo.VisibleTo(viewer))
+	if err != nil {
+		return nil, err
+	}
+	return &characterModel.CharacterResponse{Character: *c, CharacterUsage: usage[c.ID]}, nil
+}
+
+// lockCharacter loads a character with SELECT ... FOR UPDATE in tx and checks,
+// when ifMatch is set, that it is still at that version.
+// canEditCharacter reports whether viewer may change or delete c.
+func canEditCharacter(c *characterModel.Character, viewer common.Viewer) bool {
+	return viewer.Admin || c.CreatedBy == viewer.UserID
+}
+
+func lockCharacter(ctx context.Context, tx *gorm.DB, id uuid.UUID, ifMatch int) (*characterModel.Character, error) {
+	c, err := characterRepo.NewRepository(tx).GetByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	if err := common.CheckVersion(c.Version, ifMatch); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,209 @@
+package character
+
+import (
+	"bytes"
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	videoService "smart-scene-app-api/internal/services/video"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetVideoCoOccurrence returns the co-occurrence matrix of the characters of
+// a video.
+func (s *characterService) GetVideoCoOccurrence(videoID string, params characterModel.CoOccurrenceParams, viewer common.Viewer) (*characterModel.CoOccurrenceMatrix, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	video, err := videoService.AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newCoOccurrenceBuilder()
+	timeline, err := s.loadSceneTimeline(video, coOccurrenceSceneFilter(params))
+	if err != nil {
+		return nil, err
+	}
+	b.add(timeline)
+	return b.matrix(params.MinSharedSeconds), nil
+}
+
+// GetCoOccurrence returns the co-occurrence matrix of the characters of the
+// videos selected by filter, summed over the videos. The filter must select
+// videos by ID or tag and match at most MaxCoOccurrenceVideos videos.
+func (s *characterService) GetCoOccurrence(filter characterModel.CoOccurrenceFilter, viewer common.Viewer) (*characterModel.CoOccurrenceMatrix, error) {
+	if len(filter.VideoIDs) == 0 && !filter.Selects() {
+		return nil, fmt.Errorf("%w: video_ids, tag_ids, tag_codes or tag_ranges is required", common.ErrInvalidCoOccurrence)
+	}
+
+	clauses, err := s.coOccurrenceVideoClauses(filter, viewer)
+	if err != nil {
+		return nil, err
+	}
+	// Only what loadSceneTimeline reads is selected, and one video more than
+	// allowed so that an oversized selection is detected.
+	videos, err := videoRepo.NewRepository(s.sc.DB()).List(s.sc.Ctx(), models.QueryParams{
+		Limit:    characterModel.MaxCoOccurrenceVideos + 1,
+		Selected: []string{"videos.id", "videos.duration", "videos.metadata"},
+	}, clauses...)
+	if err != nil {
+		return nil, err
+	}
+	if len(videos) > characterModel.MaxCoOccurrenceVideos {
+		return nil, fmt.Errorf("%w: more than %d videos match", common.ErrInvalidCoOccurrence, characterModel.MaxCoOccurrenceVideos)
+	}
+
+	b := newCoOccurrenceBuilder()
+	// Timelines are loaded one video at a time so that only the appearances
+	// of one video are held in memory.
+	for _, video := range videos {
+		timeline, err := s.loadSceneTimeline(video, coOccurrenceSceneFilter(filter.CoOccurrenceParams))
+		if err != nil {
+			return nil, err
+		}
+		b.add(timeline)
+	}
+	return b.matrix(filter.MinSharedSeconds), nil
+}
+
+// coOccurrenceVideoClauses filters the videos table down to the videos of
+// filter that have appearances, applying its tag filter as the video listing
+// does.
+func (s *characterService) coOccurrenceVideoClauses(filter characterModel.CoOccurrenceFilter, viewer common.Viewer) ([]repositories.Clause, error) {
+	db := s.sc.DB()
+	clauses, err := videoService.ListFilterClauses(s.sc.Ctx(), db, videoModel.VideoFilterAndPagination{VideoTagFilter: filter.VideoTagFilter})
+	if err != nil {
+		return nil, err
+	}
+	clauses = append(clauses,
+		videoRepo.VisibleTo(viewer),
+		func(tx *gorm.DB) {
+			tx.Where("EXISTS (?)", db.Table(common.POSTGRES_TABLE_NAME_CHARACTER_APPEARANCES+" ca").
+				Select("1").
+				Where("ca.video_id = videos.id AND NOT ca.is_rejected"))
+		},
+	)
+	if len(filter.VideoIDs) > 0 {
+		clauses = append(clauses, func(tx *gorm.DB) {
+			tx.Where("videos.id IN ?", filter.VideoIDs)
+		})
+	}
+	return clauses, nil
+}
+
+func coOccurrenceSceneFilter(params characterModel.CoOccurrenceParams) characterModel.SceneSegmentFilter {
+	return characterModel.SceneSegmentFilter{
+		MinConfidence: params.MinConfidence,
+		GapTolerance:  params.GapTolerance(),
+	}
+}
+
+// coOccurrenceBuilder sums the screen times and overlaps of characters over
+// the timelines of one or more videos.
+type coOccurrenceBuilder struct {
+	videoCount int
+	characters map[uuid.UUID]*characterModel.CoOccurrenceCharacter
+	pairs      map[[2]uuid.UUID]*characterModel.CharacterCoOccurrence
+}
+
+func newCoOccurrenceBuilder() *coOccurrenceBuilder {
+	return &coOccurrenceBuilder{
+		characters: make(map[uuid.UUID]*characterModel.CoOccurrenceCharacter),
+		pairs:      make(map[[2]uuid.UUID]*characterModel.CharacterCoOccurrence),
+	}
+}
+
+// add counts the characters of a video. Shared seconds are where both
+// characters were detected; shared scenes are the scenes the scene listing
+// would return for the pair, absences up to the gap tolerance bridged.
+func (b *coOccurrenceBuilder) add(t *sceneTimeline) {
+	b.videoCount++
+	for _, id := range t.order {
+		c := t.characters[id]
+		node, ok := b.characters[id]
+		if !ok {
+			node = &characterModel.CoOccurrenceCharacter{
+				CharacterID:     id,
+				CharacterName:   c.name,
+				CharacterAvatar: c.avatar,
+			}
+			b.characters[id] = node
+		}
+		node.ScreenTime += c.detected.Len()
+		node.VideoCount++
+	}
+
+	for i, a := range t.order {
+		for _, other := range t.order[i+1:] {
+			// Bridged presences can overlap where the detections do not, so
+			// a pair sharing only scenes is kept.
+			shared := t.characters[a].detected.Intersect(t.characters[other].detected)
+			scenes := len(t.characters[a].present.Intersect(t.characters[other].present))
+			if shared.Empty() && scenes == 0 {
+				continue
+			}
+			key := [2]uuid.UUID{a, other}
+			if bytes.Compare(other[:], a[:]) < 0 {
+				key = [2]uuid.UUID{other, a}
+			}
+			pair, ok := b.pairs[key]
+			if !ok {
+				pair = &characterModel.CharacterCoOccurrence{CharacterID: key[0], OtherCharacterID: key[1]}
+				b.pairs[key] = pair
+			}
+			pair.SharedSeconds += shared.Len()
+			pair.SharedScenes += scenes
+			pair.VideoCount++
+		}
+	}
+}
+
+// matrix returns the summed matrix, leaving out the pairs sharing less than
+// minSharedSeconds.
+func (b *coOccurrenceBuilder) matrix(minSharedSeconds float64) *characterModel.CoOccurrenceMatrix {
+	m := &characterModel.CoOccurrenceMatrix{
+		VideoCount: b.videoCount,
+		Characters: make([]characterModel.CoOccurrenceCharacter, 0, len(b.characters)),
+		Pairs:      []characterModel.CharacterCoOccurrence{},
+	}
+	for _, c := range b.characters {
+		m.Characters = append(m.Characters, *c)
+	}
+	sort.Slice(m.Characters, func(i, j int) bool {
+		if m.Characters[i].ScreenTime != m.Characters[j].ScreenTime {
+			return m.Characters[i].ScreenTime > m.Characters[j].ScreenTime
+		}
+		return m.Characters[i].CharacterName < m.Characters[j].CharacterName
+	})
+
+	for _, p := range b.pairs {
+		if p.SharedSeconds < minSharedSeconds {
+			continue
+		}
+		// Either is on screen for both screen times minus the shared part.
+		union := b.characters[p.CharacterID].ScreenTime + b.characters[p.OtherCharacterID].ScreenTime - p.SharedSeconds
+		if union > 0 {
+			p.Jaccard = p.SharedSeconds / union
+		}
+		m.Pairs = append(m.Pairs, *p)
+	}
+	sort.Slice(m.Pairs, func(i, j int) bool {
+		if m.Pairs[i].SharedSeconds != m.Pairs[j].SharedSeconds {
+			return m.Pairs[i].SharedSeconds > m.Pairs[j].SharedSeconds
+		}
+		if m.Pairs[i].CharacterID != m.Pairs[j].CharacterID {
+			return bytes.Compare(m.Pairs[i].CharacterID[:], m.Pairs[j].CharacterID[:]) < 0
+		}
+		return bytes.Compare(m.Pairs[i].OtherCharacterID[:], m.Pairs[j].OtherCharacterID[:]) < 0
+	})
+	return m
+}
@@ -0,0 +1,265 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	characterRepo "smart-scene-app-api/internal/repositories/character"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	videoService "smart-scene-app-api/internal/services/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// appearanceInsertBatchSize keeps a single INSERT well under the postgres
+// bind parameter limit.
+const appearanceInsertBatchSize = 1000
+
+// videoDurationTolerance allows for the stored duration being rounded down to
+// whole seconds.
+const videoDurationTolerance = 1.0
+
+// ReplaceVideoAppearances replaces the unreviewed appearances of a video with
+// the given detections; confirmed and rejected appearances are kept. A nil viewer marks the ingest as performed by the system
+// (analysis webhook), which skips access checks. A non-zero ifMatch makes the
+// replacement conditional on the current version of the video, which it
+// increments.
+func (s *characterService) ReplaceVideoAppearances(videoID string, req characterModel.IngestAppearancesRequest, ifMatch int, viewer *common.Viewer) (*characterModel.IngestAppearancesResponse, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	var video *videoModel.Video
+	var actorID *uuid.UUID
+	if viewer != nil {
+		video, err = videoService.AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, *viewer, videoModel.AccessEditor)
+		actorID = &viewer.UserID
+	} else {
+		video, err = videoRepo.NewRepository(s.sc.DB()).GetByID(s.sc.Ctx(), uuidID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = common.ErrVideoNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDetections(video, req); err != nil {
+		return nil, err
+	}
+
+	characterIDs := distinctCharacterIDs(req.Appearances)
+	if err := s.ensureActiveCharacters(characterIDs); err != nil {
+		return nil, err
+	}
+
+	createdBy := video.CreatedBy
+	if actorID != nil {
+		createdBy = *actorID
+	}
+
+	var appearances []*characterModel.CharacterAppearance
+	var reviewed []characterModel.CharacterAppearance
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		appearanceRepo := characterRepo.NewAppearanceRepository(tx)
+
+		locked, err := videoRepo.NewRepository(tx).GetByIDForUpdate(ctx, uuidID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common.ErrVideoNotFound
+			}
+			return err
+		}
+		if err := common.CheckVersion(locked.Version, ifMatch); err != nil {
+			return err
+		}
+
+		// Reviewed appearances are kept, and detections overlapping one of the
+		// same character are dropped so that a rejection sticks and a
+		// confirmed appearance is not duplicated.
+		reviewed, err = appearanceRepo.ListReviewed(ctx, uuidID)
+		if err != nil {
+			return err
+		}
+		appearances = make([]*characterModel.CharacterAppearance, 0, len(req.Appearances))
+		for _, detection := range req.Appearances {
+			if overlapsReviewed(detection, reviewed) {
+				continue
+			}
+			appearances = append(appearances, &characterModel.CharacterAppearance{
+				ID:          uuid.New(),
+				VideoID:     uuidID,
+				CharacterID: detection.CharacterID,
+				StartFrame:  detection.StartFrame,
+				EndFrame:    detection.EndFrame,
+				StartTime:   detection.StartTime,
+				EndTime:     detection.EndTime,
+				Duration:    detection.EndTime - detection.StartTime,
+				Confidence:  detection.Confidence,
+				Metadata:    detection.Metadata,
+				CreatedBy:   createdBy,
+			})
+		}
+
+		if err := appearanceRepo.Delete(ctx, func(tx *gorm.DB) {
+			tx.Where("video_id = ? AND NOT is_confirmed AND NOT is_rejected", uuidID)
+		}); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(appearances); start += appearanceInsertBatchSize {
+			end := start + appearanceInsertBatchSize
+			if end > len(appearances) {
+				end = len(appearances)
+			}
+			if err := appearanceRepo.CreatesMultiple(ctx, appearances[start:end]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := videoRepo.NewRepository(tx).UpdateColumns(ctx, uuidID, map[string]interface{}{
+			"character_count":        countCharacters(appearances, reviewed),
+			"has_character_analysis": true,
+		}); err != nil {
+			return err
+		}
+
+		return videoService.AdvanceStatusTo(ctx, tx, uuidID, videoModel.VideoStatusCompleted, "character appearances ingested", actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &characterModel.IngestAppearancesResponse{
+		VideoID:         uuidID,
+		AppearanceCount: len(appearances),
+		ReviewedCount:   len(reviewed),
+		SkippedCount:    len(req.Appearances) - len(appearances),
+		CharacterCount:  countCharacters(appearances, reviewed),
+		Status:          videoModel.VideoStatusCompleted,
+	}, nil
+}
+
+func (s *characterService) ensureActiveCharacters(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	characters, err := s.characterRepo.List(s.sc.Ctx(), models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("id IN ?", ids)
+	})
+	if err != nil {
+		return err
+	}
+
+	found := make(map[uuid.UUID]*characterModel.Character, len(characters))
+	for _, c := range characters {
+		found[c.ID] = c
+	}
+	for _, id := range ids {
+		c, ok := found[id]
+		if !ok {
+			return fmt.Errorf("%w: %s", common.ErrCharacterNotFound, id)
+		}
+		if !c.IsActive {
+			return fmt.Errorf("%w: %s", common.ErrCharacterInactive, id)
+		}
+	}
+	return nil
+}
+
+// validateDetections checks every detection against the video: time ranges
+// must be ordered and inside the duration, and frame ranges must be ordered and
+// inside duration*fps when the frame rate is known.
+func validateDetections(video *videoModel.Video, req characterModel.IngestAppearancesRequest) error {
+	fps := videoFPS(video, req.FPS)
+	maxTime := maxVideoTime(video)
+	maxFrame := math.Inf(1)
+	if fps > 0 && video.Duration > 0 {
+		maxFrame = math.Ceil(maxTime * fps)
+	}
+
+	for i, d := range req.Appearances {
+		switch {
+		case d.EndTime < d.StartTime:
+			return fmt.Errorf("%w: appearance %d: end_time is before start_time", common.ErrInvalidAppearance, i)
+		case d.EndTime > maxTime:
+			return fmt.Errorf("%w: appearance %d: end_time %.3f exceeds video duration %d", common.ErrInvalidAppearance, i, d.EndTime, video.Duration)
+		case d.EndFrame < d.StartFrame:
+			return fmt.Errorf("%w: appearance %d: end_frame is before start_frame", common.ErrInvalidAppearance, i)
+		case float64(d.EndFrame) > maxFrame:
+			return fmt.Errorf("%w: appearance %d: end_frame %d exceeds video frame count", common.ErrInvalidAppearance, i, d.EndFrame)
+		}
+	}
+	return nil
+}
+
+// videoFPS returns requested, or metadata.fps of the video when requested is
+// 0. It returns 0 when the frame rate is unknown.
+func videoFPS(video *videoModel.Video, requested float64) float64 {
+	if requested > 0 {
+		return requested
+	}
+	if value, ok := video.Metadata["fps"].(float64); ok && value > 0 {
+		return value
+	}
+	return 0
+}
+
+// maxVideoTime is the latest time an appearance of video may end at.
+func maxVideoTime(video *videoModel.Video) float64 {
+	// Duration 0 means the duration has not been probed yet.
+	if video.Duration <= 0 {
+		return math.Inf(1)
+	}
+	return float64(video.Duration) + videoDurationTolerance
+}
+
+func distinctCharacterIDs(detections []characterModel.AppearanceDetection) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, d := range detections {
+		if !seen[d.CharacterID] {
+			seen[d.CharacterID] = true
+			ids = append(ids, d.CharacterID)
+		}
+	}
+	return ids
+}
+
+// overlapsReviewed reports whether a detection overlaps a reviewed appearance
+// of the same character.
+func overlapsReviewed(d characterModel.AppearanceDetection, reviewed []characterModel.CharacterAppearance) bool {
+	for _, a := range reviewed {
+		if a.CharacterID != d.CharacterID {
+			continue
+		}
+		if (d.StartTime < a.EndTime && a.StartTime < d.EndTime) ||
+			(d.StartTime == a.StartTime && d.EndTime == a.EndTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// countCharacters counts the distinct characters of a video once ingested,
+// rejected appearances excluded.
+func countCharacters(appearances []*characterModel.CharacterAppearance, reviewed []characterModel.CharacterAppearance) int {
+	seen := make(map[uuid.UUID]bool)
+	for _, a := range appearances {
+		seen[a.CharacterID] = true
+	}
+	for _, a := range reviewed {
+		if !a.IsRejected {
+			seen[a.CharacterID] = true
+		}
+	}
+	return len(seen)
+}
@@ -0,0 +1,320 @@
+package character
+
+import (
+	"errors"
+	"smart-scene-app-api/common"
+	characterModel "smart-scene-app-api/internal/models/character"
+	characterRepo "smart-scene-app-api/internal/repositories/character"
+	tagRepo "smart-scene-app-api/internal/repositories/tag"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// errMergeDryRun rolls back the transaction of a dry-run merge.
+var errMergeDryRun = errors.New("dry run")
+
+type videoTagKey struct {
+	videoID uuid.UUID
+	tagID   int
+}
+
+// MergeCharacters moves the appearances and video tags of the source
+// characters to the target in a single transaction, combines their metadata
+// into the target's and deactivates or deletes the sources. Video tags the
+// target already has on the same video are dropped. A record of the merge is
+// kept for UndoCharacterMerge. On a dry run the merge is performed and rolled
+// back, so the counts reported are exact. Merges edit videos across the
+// library, so only admins can run them.
+func (s *characterService) MergeCharacters(targetID string, req characterModel.MergeCharactersRequest, viewer common.Viewer) (*characterModel.CharacterMergeResponse, error) {
+	targetUUID, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if !viewer.Admin {
+		return nil, common.ErrCharacterForbidden
+	}
+
+	sourceIDs := make([]uuid.UUID, 0, len(req.SourceIDs))
+	seen := map[uuid.UUID]bool{targetUUID: true}
+	for _, id := range req.SourceIDs {
+		if seen[id] {
+			if id == targetUUID {
+				return nil, common.ErrInvalidCharacterMerge
+			}
+			continue
+		}
+		seen[id] = true
+		sourceIDs = append(sourceIDs, id)
+	}
+
+	response := &characterModel.CharacterMergeResponse{
+		DryRun:         req.DryRun,
+		TargetID:       targetUUID,
+		SourceIDs:      sourceIDs,
+		SourcesDeleted: req.DeleteSources,
+	}
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		repo := characterRepo.NewRepository(tx)
+		mergeRepo := characterRepo.NewMergeRepository(tx)
+
+		target, err := lockCharacter(ctx, tx, targetUUID, 0)
+		if err != nil {
+			return err
+		}
+		snapshot := characterModel.CharacterMergeSnapshot{
+			Target:         *target,
+			SourcesDeleted: req.DeleteSources,
+			Appearances:    make(map[uuid.UUID][]uuid.UUID),
+			VideoTags:      make(map[uuid.UUID][]int),
+		}
+		for _, id := range sourceIDs {
+			source, err := lockCharacter(ctx, tx, id, 0)
+			if errors.Is(err, common.ErrCharacterNotFound) {
+				return common.ErrInvalidCharacterMerge
+			}
+			if err != nil {
+				return err
+			}
+			snapshot.Sources = append(snapshot.Sources, *source)
+		}
+
+		appearances, err := mergeRepo.ListAppearances(ctx, sourceIDs)
+		if err != nil {
+			return err
+		}
+		videoIDs := make(map[uuid.UUID]bool)
+		for _, a := range appearances {
+			snapshot.Appearances[a.CharacterID] = append(snapshot.Appearances[a.CharacterID], a.ID)
+			videoIDs[a.VideoID] = true
+		}
+		for sourceID, ids := range snapshot.Appearances {
+			if err := mergeRepo.ReassignAppearances(ctx, ids, sourceID, targetUUID); err != nil {
+				return err
+			}
+		}
+
+		videoTags, err := mergeRepo.ListVideoTags(ctx, append([]uuid.UUID{targetUUID}, sourceIDs...))
+		if err != nil {
+			return err
+		}
+		held := make(map[videoTagKey]bool)
+		for _, vt := range videoTags {
+			if vt.CharacterID == targetUUID {
+				held[videoTagKey{vt.VideoID, vt.TagID}] = true
+			}
+		}
+		var dropped []int
+		usageDeltas := make(map[int]int)
+		for _, vt := range videoTags {
+			if vt.CharacterID == targetUUID {
+				continue
+			}
+			videoIDs[vt.VideoID] = true
+			key := videoTagKey{vt.VideoID, vt.TagID}
+			if held[key] {
+				snapshot.DroppedVideoTags = append(snapshot.DroppedVideoTags, vt)
+				dropped = append(dropped, vt.ID)
+				usageDeltas[vt.TagID]--
+				continue
+			}
+			held[key] = true
+			snapshot.VideoTags[vt.CharacterID] = append(snapshot.VideoTags[vt.CharacterID], vt.ID)
+		}
+		if err := mergeRepo.DeleteVideoTags(ctx, dropped); err != nil {
+			return err
+		}
+		if err := tagRepo.NewTagMainRepository(tx).AdjustUsageCounts(ctx, usageDeltas); err != nil {
+			return err
+		}
+		for sourceID, ids := range snapshot.VideoTags {
+			if err := mergeRepo.ReassignVideoTags(ctx, ids, sourceID, targetUUID); err != nil {
+				return err
+			}
+			response.VideoTagsMoved += len(ids)
+		}
+
+		for id := range videoIDs {
+			snapshot.VideoIDs = append(snapshot.VideoIDs, id)
+		}
+		if err := repo.RecountVideoCharacters(ctx, snapshot.VideoIDs); err != nil {
+			return err
+		}
+
+		metadata := combineMetadata(target.Metadata, snapshot.Sources)
+		if _, err := repo.UpdateColumns(ctx, targetUUID, map[string]interface{}{
+			"metadata":   metadata,
+			"updated_by": viewer.UserID,
+		}); err != nil {
+			return err
+		}
+		if req.DeleteSources {
+			err = repo.Delete(ctx, func(tx *gorm.DB) {
+				tx.Where("id IN ?", sourceIDs)
+			})
+		} else {
+			err = repo.UpdatesColumnsByConditions(ctx, map[string]interface{}{
+				"is_active":  false,
+				"updated_by": viewer.UserID,
+			}, func(tx *gorm.DB) {
+				tx.Where("id IN ?", sourceIDs)
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		response.AppearancesMoved = len(appearances)
+		response.VideoTagsDropped = len(dropped)
+		response.AffectedVideos = len(snapshot.VideoIDs)
+		response.Metadata = metadata
+		if req.DryRun {
+			return errMergeDryRun
+		}
+
+		merge, err := mergeRepo.Create(ctx, &characterModel.CharacterMerge{
+			CreatedBy: viewer.UserID,
+			TargetID:  targetUUID,
+			Snapshot:  snapshot,
+		})
+		if err != nil {
+			return err
+		}
+		response.MergeID = &merge.ID
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMergeDryRun) {
+		return nil, err
+	}
+	return response, nil
+}
+
+// UndoCharacterMerge reverts a merge: deleted sources are recreated, sources
+// and the target get back their previous state, and the moved appearances and
+// video tags still attached to the target return to their source. Dropped
+// video tags are restored unless an equal one has been added since.
+func (s *characterService) UndoCharacterMerge(mergeID string, viewer common.Viewer) (*characterModel.CharacterMergeResponse, error) {
+	uuidID, err := uuid.Parse(mergeID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if !viewer.Admin {
+		return nil, common.ErrCharacterForbidden
+	}
+
+	var response *characterModel.CharacterMergeResponse
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		repo := characterRepo.NewRepository(tx)
+		mergeRepo := characterRepo.NewMergeRepository(tx)
+
+		merge, err := mergeRepo.GetByIDForUpdate(ctx, uuidID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common.ErrCharacterMergeNotFound
+			}
+			return err
+		}
+		if merge.UndoneAt != nil {
+			return common.ErrCharacterMergeUndone
+		}
+		snapshot := merge.Snapshot
+
+		if snapshot.SourcesDeleted {
+			if err := mergeRepo.RestoreCharacters(ctx, snapshot.Sources); err != nil {
+				return err
+			}
+		} else {
+			for _, source := range snapshot.Sources {
+				if _, err := repo.UpdateColumns(ctx, source.ID, map[string]interface{}{
+					"is_active":  source.IsActive,
+					"updated_by": viewer.UserID,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := repo.UpdateColumns(ctx, merge.TargetID, map[string]interface{}{
+			"metadata":   snapshot.Target.Metadata,
+			"updated_by": viewer.UserID,
+		}); err != nil {
+			return err
+		}
+
+		for sourceID, ids := range snapshot.Appearances {
+			if err := mergeRepo.ReassignAppearances(ctx, ids, merge.TargetID, sourceID); err != nil {
+				return err
+			}
+		}
+		for sourceID, ids := range snapshot.VideoTags {
+			if err := mergeRepo.ReassignVideoTags(ctx, ids, merge.TargetID, sourceID); err != nil {
+				return err
+			}
+		}
+		restored, err := mergeRepo.RestoreVideoTags(ctx, snapshot.DroppedVideoTags)
+		if err != nil {
+			return err
+		}
+		if err := tagRepo.NewTagMainRepository(tx).AdjustUsageCounts(ctx, restored); err != nil {
+			return err
+		}
+		if err := repo.RecountVideoCharacters(ctx, snapshot.VideoIDs); err != nil {
+			return err
+		}
+
+		undoneAt := time.Now()
+		if _, err := mergeRepo.UpdateColumns(ctx, uuidID, map[string]interface{}{
+			"undone_at": undoneAt,
+			"undone_by": viewer.UserID,
+		}); err != nil {
+			return err
+		}
+		merge.UndoneAt = &undoneAt
+		response = toMergeResponse(merge)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// combineMetadata merges the metadata of the sources, in order, under the
+// metadata of the target: keys of the target win, nested objects are merged.
+func combineMetadata(target common.JSON, sources []characterModel.Character) common.JSON {
+	var combined interface{} = map[string]interface{}{}
+	for _, source := range sources {
+		if source.Metadata != nil {
+			combined = common.MergePatch(combined, map[string]interface{}(source.Metadata))
+		}
+	}
+	if target != nil {
+		combined = common.MergePatch(combined, map[string]interface{}(target))
+	}
+	return common.JSON(combined.(map[string]interface{}))
+}
+
+func toMergeResponse(merge *characterModel.CharacterMerge) *characterModel.CharacterMergeResponse {
+	snapshot := merge.Snapshot
+	response := &characterModel.CharacterMergeResponse{
+		MergeID:          &merge.ID,
+		TargetID:         merge.TargetID,
+		SourcesDeleted:   snapshot.SourcesDeleted,
+		VideoTagsDropped: len(snapshot.DroppedVideoTags),
+		AffectedVideos:   len(snapshot.VideoIDs),
+		Metadata:         snapshot.Target.Metadata,
+		UndoneAt:         merge.UndoneAt,
+	}
+	for _, source := range snapshot.Sources {
+		response.SourceIDs = append(response.SourceIDs, source.ID)
+	}
+	for _, ids := range snapshot.Appearances {
+		response.AppearancesMoved += len(ids)
+	}
+	for _, ids := range snapshot.VideoTags {
+		response.VideoTagsMoved += len(ids)
+	}
+	return response
+}
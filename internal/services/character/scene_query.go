@@ -0,0 +1,221 @@
+package character
+
+import (
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/pkg/interval"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Limits on the size of a scene expression, which is evaluated in memory.
+const (
+	maxSceneExpressionDepth = 32
+	maxSceneExpressionNodes = 256
+)
+
+// QueryVideoScenes returns the scenes of a video where a boolean expression
+// over its characters holds, in the shape of GetVideoScenesWithCharacters.
+func (s *characterService) QueryVideoScenes(videoID string, req characterModel.SceneQueryRequest, page models.BaseRequestParamsUri, viewer common.Viewer) (*characterModel.VideoSceneListResponse, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	video, err := videoService.AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	page.VerifyPaging()
+	if req.MaxDuration > 0 && req.MaxDuration < req.MinDuration {
+		return nil, common.ErrInvalidSceneFilter
+	}
+	nodes := 0
+	if err := validateSceneExpression(req.Expression, 1, &nodes); err != nil {
+		return nil, err
+	}
+	names, err := s.resolveCharacterNames(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := characterModel.SceneSegmentFilter{
+		MinConfidence: req.MinConfidence,
+		GapTolerance:  req.GapTolerance(),
+		MinDuration:   req.MinDuration,
+		MaxDuration:   req.MaxDuration,
+	}
+	timeline, err := s.loadSceneTimeline(video, filter)
+	if err != nil {
+		return nil, err
+	}
+	set := timeline.evaluate(req.Expression, names, false)
+	return paginateScenes(timeline.scenes(set, filter), page), nil
+}
+
+// includeExcludeExpression expresses "all of include and none of exclude".
+func includeExcludeExpression(include, exclude []uuid.UUID) characterModel.SceneExpression {
+	expr := characterModel.SceneExpression{Op: characterModel.SceneOpAnd}
+	for _, id := range include {
+		expr.Args = append(expr.Args, characterExpression(id))
+	}
+	if len(exclude) > 0 {
+		anyExcluded := characterModel.SceneExpression{Op: characterModel.SceneOpOr}
+		for _, id := range exclude {
+			anyExcluded.Args = append(anyExcluded.Args, characterExpression(id))
+		}
+		expr.Args = append(expr.Args, characterModel.SceneExpression{
+			Op:   characterModel.SceneOpNot,
+			Args: []characterModel.SceneExpression{anyExcluded},
+		})
+	}
+	return expr
+}
+
+func characterExpression(id uuid.UUID) characterModel.SceneExpression {
+	return characterModel.SceneExpression{Op: characterModel.SceneOpCharacter, CharacterID: &id}
+}
+
+func validateSceneExpression(expr characterModel.SceneExpression, depth int, nodes *int) error {
+	*nodes++
+	if depth > maxSceneExpressionDepth || *nodes > maxSceneExpressionNodes {
+		return fmt.Errorf("%w: expression is larger than %d nodes or deeper than %d levels",
+			common.ErrInvalidSceneExpression, maxSceneExpressionNodes, maxSceneExpressionDepth)
+	}
+
+	switch expr.Op {
+	case characterModel.SceneOpCharacter:
+		if (expr.CharacterID == nil) == (strings.TrimSpace(expr.Name) == "") {
+			return fmt.Errorf("%w: character needs exactly one of character_id or name", common.ErrInvalidSceneExpression)
+		}
+		if len(expr.Args) > 0 {
+			return fmt.Errorf("%w: character takes no args", common.ErrInvalidSceneExpression)
+		}
+		return nil
+	case characterModel.SceneOpNot:
+		if len(expr.Args) != 1 {
+			return fmt.Errorf("%w: not takes exactly one arg", common.ErrInvalidSceneExpression)
+		}
+	case characterModel.SceneOpAtLeast:
+		if expr.N < 1 || expr.N > len(expr.Args) {
+			return fmt.Errorf("%w: at_least needs n between 1 and the number of args", common.ErrInvalidSceneExpression)
+		}
+	case characterModel.SceneOpAnd, characterModel.SceneOpOr, characterModel.SceneOpCast:
+		if len(expr.Args) == 0 {
+			return fmt.Errorf("%w: %s needs at least one arg", common.ErrInvalidSceneExpression, expr.Op)
+		}
+	default:
+		return fmt.Errorf("%w: unknown op %q", common.ErrInvalidSceneExpression, expr.Op)
+	}
+
+	for _, arg := range expr.Args {
+		if expr.Op == characterModel.SceneOpCast && arg.Op != characterModel.SceneOpCharacter {
+			return fmt.Errorf("%w: cast args must be characters", common.ErrInvalidSceneExpression)
+		}
+		if err := validateSceneExpression(arg, depth+1, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveCharacterNames maps the lowercased character names used in expr to
+// the characters of the catalog.
+func (s *characterService) resolveCharacterNames(expr characterModel.SceneExpression) (map[string]uuid.UUID, error) {
+	var names []string
+	var collect func(e characterModel.SceneExpression)
+	collect = func(e characterModel.SceneExpression) {
+		if e.Op == characterModel.SceneOpCharacter && e.CharacterID == nil {
+			names = append(names, strings.ToLower(strings.TrimSpace(e.Name)))
+		}
+		for _, arg := range e.Args {
+			collect(arg)
+		}
+	}
+	collect(expr)
+
+	resolved := make(map[string]uuid.UUID, len(names))
+	if len(names) == 0 {
+		return resolved, nil
+	}
+	characters, err := s.characterRepo.List(s.sc.Ctx(), models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("LOWER(name) IN ?", names)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range characters {
+		resolved[strings.ToLower(c.Name)] = c.ID
+	}
+	for _, name := range names {
+		if _, ok := resolved[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown character %q", common.ErrInvalidSceneExpression, name)
+		}
+	}
+	return resolved, nil
+}
+
+// evaluate returns the set of instants where expr holds. Under an odd number
+// of nots (negated), characters count only where they were detected, and
+// elsewhere where they are present with short absences bridged, so the gap
+// tolerance always works in favour of the expression.
+func (t *sceneTimeline) evaluate(expr characterModel.SceneExpression, names map[string]uuid.UUID, negated bool) interval.Set[float64] {
+	operands := func(negated bool) []interval.Set[float64] {
+		sets := make([]interval.Set[float64], 0, len(expr.Args))
+		for _, arg := range expr.Args {
+			sets = append(sets, t.evaluate(arg, names, negated))
+		}
+		return sets
+	}
+
+	switch expr.Op {
+	case characterModel.SceneOpCharacter:
+		id := expressionCharacterID(expr, names)
+		if negated {
+			return t.detections(id)
+		}
+		return t.presence(id)
+	case characterModel.SceneOpAnd:
+		return interval.IntersectAll(operands(negated)...)
+	case characterModel.SceneOpOr:
+		return interval.UnionAll(operands(negated)...)
+	case characterModel.SceneOpNot:
+		return interval.IntersectAll(operands(!negated)...).Complement(t.bounds)
+	case characterModel.SceneOpAtLeast:
+		return interval.AtLeast(expr.N, operands(negated)...)
+	case characterModel.SceneOpCast:
+		// The cast is on screen and nobody else is.
+		cast := make(map[uuid.UUID]bool, len(expr.Args))
+		for _, arg := range expr.Args {
+			cast[expressionCharacterID(arg, names)] = true
+		}
+		var others []interval.Set[float64]
+		for _, id := range t.order {
+			if cast[id] {
+				continue
+			}
+			if negated {
+				others = append(others, t.presence(id))
+			} else {
+				others = append(others, t.detections(id))
+			}
+		}
+		return interval.IntersectAll(operands(negated)...).Difference(interval.UnionAll(others...))
+	}
+	return nil
+}
+
+// expressionCharacterID returns the character a character expression refers
+// to, looking names up in names.
+func expressionCharacterID(expr characterModel.SceneExpression, names map[string]uuid.UUID) uuid.UUID {
+	if expr.CharacterID != nil {
+		return *expr.CharacterID
+	}
+	return names[strings.ToLower(strings.TrimSpace(expr.Name))]
+}
@@ -0,0 +1,219 @@
+package character
+
+import (
+	"fmt"
+	"math"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/pkg/interval"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// GetVideoScenesWithCharacters returns the scenes of a video where every
+// included character is present and no excluded one is: the expression
+// and(include..., not(or(exclude...))) of QueryVideoScenes.
+func (s *characterService) GetVideoScenesWithCharacters(videoID string, queryParams characterModel.VideoSceneFilterAndPagination, viewer common.Viewer) (*characterModel.VideoSceneListResponse, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	video, err := videoService.AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams.VerifyPaging()
+	if queryParams.MaxDuration > 0 && queryParams.MaxDuration < queryParams.MinDuration {
+		return nil, common.ErrInvalidSceneFilter
+	}
+	filter := characterModel.SceneSegmentFilter{
+		MinConfidence: queryParams.MinConfidence,
+		GapTolerance:  queryParams.GapTolerance(),
+		MinDuration:   queryParams.MinDuration,
+		MaxDuration:   queryParams.MaxDuration,
+	}
+
+	var scenes []characterModel.VideoScene
+	if len(queryParams.IncludeCharacters) > 0 {
+		timeline, err := s.loadSceneTimeline(video, filter)
+		if err != nil {
+			return nil, err
+		}
+		expr := includeExcludeExpression(queryParams.IncludeCharacters, queryParams.ExcludeCharacters)
+		scenes = timeline.scenes(timeline.evaluate(expr, nil, false), filter)
+	}
+
+	return paginateScenes(scenes, queryParams.BaseRequestParamsUri), nil
+}
+
+// sceneTimeline holds the appearances of a video per character, in seconds.
+type sceneTimeline struct {
+	videoID uuid.UUID
+	fps     float64
+	// bounds spans the video, or its appearances when they run past the
+	// recorded duration.
+	bounds     interval.Interval[float64]
+	characters map[uuid.UUID]*timelineCharacter
+	// order lists the characters by first appearance.
+	order []uuid.UUID
+}
+
+type timelineCharacter struct {
+	name        string
+	avatar      string
+	appearances []characterModel.SceneAppearance
+	// detected is where the character was detected; present additionally
+	// bridges the absences up to the gap tolerance.
+	detected interval.Set[float64]
+	present  interval.Set[float64]
+}
+
+// loadSceneTimeline reads the appearances of a video that pass filter and
+// builds the interval sets of its characters.
+func (s *characterService) loadSceneTimeline(video *videoModel.Video, filter characterModel.SceneSegmentFilter) (*sceneTimeline, error) {
+	appearances, err := s.appearanceRepo.ListSceneAppearances(s.sc.Ctx(), video.ID, filter.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &sceneTimeline{
+		videoID:    video.ID,
+		fps:        videoFPS(video, 0),
+		bounds:     interval.Interval[float64]{End: float64(video.Duration)},
+		characters: make(map[uuid.UUID]*timelineCharacter),
+	}
+	for _, a := range appearances {
+		c, ok := t.characters[a.CharacterID]
+		if !ok {
+			c = &timelineCharacter{name: a.CharacterName, avatar: a.CharacterAvatar}
+			t.characters[a.CharacterID] = c
+			t.order = append(t.order, a.CharacterID)
+		}
+		c.appearances = append(c.appearances, a)
+		t.bounds.End = math.Max(t.bounds.End, a.EndTime)
+	}
+	for _, c := range t.characters {
+		spans := make([]interval.Interval[float64], 0, len(c.appearances))
+		for _, a := range c.appearances {
+			spans = append(spans, interval.Interval[float64]{Start: a.StartTime, End: a.EndTime})
+		}
+		c.detected = interval.New(spans...)
+		c.present = c.detected.BridgeGaps(filter.GapTolerance)
+	}
+	return t, nil
+}
+
+// presence is where a character counts as present, short absences bridged.
+func (t *sceneTimeline) presence(id uuid.UUID) interval.Set[float64] {
+	if c, ok := t.characters[id]; ok {
+		return c.present
+	}
+	return nil
+}
+
+// detections is where a character was actually detected. Exclusions use it so
+// that bridging never removes time where the character was absent.
+func (t *sceneTimeline) detections(id uuid.UUID) interval.Set[float64] {
+	if c, ok := t.characters[id]; ok {
+		return c.detected
+	}
+	return nil
+}
+
+// scenes turns the intervals of set that satisfy the duration bounds of filter
+// into scenes listing the characters detected in each.
+func (t *sceneTimeline) scenes(set interval.Set[float64], filter characterModel.SceneSegmentFilter) []characterModel.VideoScene {
+	set = set.MinLength(filter.MinDuration)
+	if filter.MaxDuration > 0 {
+		set = set.MaxLength(filter.MaxDuration)
+	}
+
+	scenes := make([]characterModel.VideoScene, 0, len(set))
+	for i, span := range set {
+		characters := t.charactersIn(span)
+		scenes = append(scenes, characterModel.VideoScene{
+			VideoID:            t.videoID,
+			SceneID:            fmt.Sprintf("segment_%d_%.1f_%.1f", i+1, span.Start, span.End),
+			StartTime:          span.Start,
+			EndTime:            span.End,
+			Duration:           span.Len(),
+			StartFrame:         t.frameAt(span.Start),
+			EndFrame:           t.frameAt(span.End),
+			CharacterCount:     len(characters),
+			Characters:         characters,
+			StartTimeFormatted: formatSecondsToTime(span.Start),
+			EndTimeFormatted:   formatSecondsToTime(span.End),
+		})
+	}
+	return scenes
+}
+
+// charactersIn lists the characters detected during span, each with the part
+// of span they cover and the mean confidence of their appearances there.
+func (t *sceneTimeline) charactersIn(span interval.Interval[float64]) []characterModel.VideoSceneCharacter {
+	characters := []characterModel.VideoSceneCharacter{}
+	for _, id := range t.order {
+		c := t.characters[id]
+		covered := c.detected.Clip(span)
+		if covered.Empty() {
+			continue
+		}
+
+		var confidence float64
+		var count int
+		for _, a := range c.appearances {
+			if a.StartTime < span.End && span.Start < a.EndTime {
+				confidence += a.Confidence
+				count++
+			}
+		}
+		bounds := covered.Bounds()
+		characters = append(characters, characterModel.VideoSceneCharacter{
+			CharacterID:     id,
+			CharacterName:   c.name,
+			CharacterAvatar: c.avatar,
+			Confidence:      confidence / float64(count),
+			StartTime:       bounds.Start,
+			EndTime:         bounds.End,
+			StartFrame:      t.frameAt(bounds.Start),
+			EndFrame:        t.frameAt(bounds.End),
+		})
+	}
+	sort.SliceStable(characters, func(i, j int) bool {
+		return characters[i].StartTime < characters[j].StartTime
+	})
+	return characters
+}
+
+// frameAt converts a time to a frame number, 0 when the frame rate of the
+// video is unknown.
+func (t *sceneTimeline) frameAt(seconds float64) int {
+	return int(math.Round(seconds * t.fps))
+}
+
+func paginateScenes(scenes []characterModel.VideoScene, page models.BaseRequestParamsUri) *characterModel.VideoSceneListResponse {
+	total := len(scenes)
+	offset := (page.Page - 1) * page.PageSize
+	switch {
+	case offset >= total:
+		scenes = []characterModel.VideoScene{}
+	case offset+page.PageSize < total:
+		scenes = scenes[offset : offset+page.PageSize]
+	default:
+		scenes = scenes[offset:]
+	}
+
+	return &characterModel.VideoSceneListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    total,
+			Page:     page.Page,
+			PageSize: page.PageSize,
+		},
+		Items: scenes,
+	}
+}
@@ -0,0 +1,606 @@
+package collection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	collectionModel "smart-scene-app-api/internal/models/collection"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+	collectionRepo "smart-scene-app-api/internal/repositories/collection"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	"smart-scene-app-api/server"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	GetCollections(queryParams collectionModel.CollectionFilterAndPagination, viewer common.Viewer) (*collectionModel.CollectionListResponse, error)
+	GetCollection(id string, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	CreateCollection(req collectionModel.CreateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	UpdateCollection(id string, req collectionModel.UpdateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	DeleteCollection(id string, viewer common.Viewer) error
+	DuplicateCollection(id string, req collectionModel.DuplicateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	AddItems(id string, req collectionModel.AddCollectionItemsRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	UpdateItem(id string, itemID string, req collectionModel.UpdateCollectionItemRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	RemoveItem(id string, itemID string, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	ReorderItems(id string, req collectionModel.ReorderCollectionItemsRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error)
+	GetCollectionSharing(id string, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error)
+	SetCollectionVisibility(id string, req videoModel.VideoVisibilityRequest, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error)
+	ShareCollection(id string, req videoModel.VideoShareRequest, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error)
+	UnshareCollection(id string, shareID string, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error)
+}
+
+type collectionService struct {
+	sc             server.ServerContext
+	collectionRepo collectionRepo.Repository
+	shareRepo      collectionRepo.ShareRepository
+}
+
+func NewCollectionService(sc server.ServerContext) Service {
+	return &collectionService{
+		sc:             sc,
+		collectionRepo: collectionRepo.NewRepository(sc.DB()),
+		shareRepo:      collectionRepo.NewShareRepository(sc.DB()),
+	}
+}
+
+// GetCollections lists the collections visible to viewer.
+func (s *collectionService) GetCollections(queryParams collectionModel.CollectionFilterAndPagination, viewer common.Viewer) (*collectionModel.CollectionListResponse, error) {
+	queryParams.VerifyPaging()
+
+	filters := []repositories.Clause{collectionRepo.VisibleTo(viewer)}
+	if queryParams.Name != "" {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("name ILIKE ?", "%"+queryParams.Name+"%")
+		})
+	}
+	if queryParams.Owned {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("created_by = ?", viewer.UserID)
+		})
+	}
+
+	sort := queryParams.Sort
+	if sort == "" {
+		sort = "updated_at.desc"
+	}
+	// Checked up front so that an invalid sort is reported even when no
+	// collection matches.
+	if _, err := (models.QuerySort{Origin: sort}).Parse(collectionModel.Collection{}.SortColumns()); err != nil {
+		return nil, err
+	}
+
+	total, err := s.collectionRepo.Count(s.sc.Ctx(), models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &collectionModel.CollectionListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    int(total),
+			Page:     queryParams.Page,
+			PageSize: queryParams.PageSize,
+		},
+		Items: []collectionModel.CollectionResponse{},
+	}
+	if total == 0 {
+		return response, nil
+	}
+
+	collections, err := s.collectionRepo.List(s.sc.Ctx(), models.QueryParams{
+		Limit:     queryParams.PageSize,
+		Offset:    (queryParams.Page - 1) * queryParams.PageSize,
+		QuerySort: models.QuerySort{Origin: sort},
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(collections))
+	for _, c := range collections {
+		ids = append(ids, c.ID)
+	}
+	counts, err := s.collectionRepo.CountItems(s.sc.Ctx(), ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range collections {
+		item := toCollectionResponse(c)
+		item.ItemCount = counts[c.ID]
+		response.Items = append(response.Items, item)
+	}
+	return response, nil
+}
+
+func (s *collectionService) GetCollection(id string, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	c, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(s.sc.DB(), c, viewer)
+}
+
+func (s *collectionService) CreateCollection(req collectionModel.CreateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = videoModel.VisibilityPrivate
+	}
+	if !videoModel.IsValidVisibility(visibility) {
+		return nil, common.ErrInvalidVisibility
+	}
+
+	var created *collectionModel.Collection
+	err := s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = collectionRepo.NewRepository(tx).Create(s.sc.Ctx(), &collectionModel.Collection{
+			Name:        req.Name,
+			Description: req.Description,
+			Visibility:  visibility,
+			CreatedBy:   viewer.UserID,
+			UpdatedBy:   viewer.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		return s.insertItems(tx, created.ID, 0, req.Items, viewer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(s.sc.DB(), created, viewer)
+}
+
+// UpdateCollection renames a collection or changes its description.
+func (s *collectionService) UpdateCollection(id string, req collectionModel.UpdateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if _, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessEditor, false); err != nil {
+		return nil, err
+	}
+
+	columns := map[string]interface{}{"updated_by": viewer.UserID}
+	if req.Name != nil {
+		columns["name"] = *req.Name
+	}
+	if req.Description != nil {
+		columns["description"] = *req.Description
+	}
+	updated, err := s.collectionRepo.UpdateColumns(s.sc.Ctx(), uuidID, columns)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(s.sc.DB(), updated, viewer)
+}
+
+func (s *collectionService) DeleteCollection(id string, viewer common.Viewer) error {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return common.ErrInvalidUUID
+	}
+	return s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := authorizeCollection(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessOwner, true); err != nil {
+			return err
+		}
+		return collectionRepo.NewRepository(tx).DeleteWithItems(s.sc.Ctx(), uuidID)
+	})
+}
+
+// DuplicateCollection copies a collection the viewer can see, with its items,
+// into a new private collection owned by the viewer. Shares are not copied.
+func (s *collectionService) DuplicateCollection(id string, req collectionModel.DuplicateCollectionRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	source, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer, false)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	var created *collectionModel.Collection
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		items, err := collectionRepo.NewItemRepository(tx).ListByCollectionID(s.sc.Ctx(), uuidID)
+		if err != nil {
+			return err
+		}
+
+		created, err = collectionRepo.NewRepository(tx).Create(s.sc.Ctx(), &collectionModel.Collection{
+			Name:        name,
+			Description: source.Description,
+			Visibility:  videoModel.VisibilityPrivate,
+			CreatedBy:   viewer.UserID,
+			UpdatedBy:   viewer.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		// Stale items are copied too, so the copy matches what was shown.
+		copies := make([]*collectionModel.CollectionItem, 0, len(items))
+		for _, item := range items {
+			copies = append(copies, &collectionModel.CollectionItem{
+				CollectionID: created.ID,
+				Position:     item.Position,
+				VideoID:      item.VideoID,
+				StartTime:    item.StartTime,
+				EndTime:      item.EndTime,
+				Note:         item.Note,
+				CreatedBy:    viewer.UserID,
+			})
+		}
+		return collectionRepo.NewItemRepository(tx).CreatesMultiple(s.sc.Ctx(), copies)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(s.sc.DB(), created, viewer)
+}
+
+func (s *collectionService) AddItems(id string, req collectionModel.AddCollectionItemsRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	return s.writeItems(id, viewer, func(tx *gorm.DB, c *collectionModel.Collection, items []collectionModel.CollectionItem) error {
+		position := len(items)
+		if req.Position != nil && *req.Position < position {
+			position = *req.Position
+		}
+		return s.insertItems(tx, c.ID, position, req.Items, viewer)
+	})
+}
+
+func (s *collectionService) UpdateItem(id string, itemID string, req collectionModel.UpdateCollectionItemRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	return s.writeItems(id, viewer, func(tx *gorm.DB, c *collectionModel.Collection, items []collectionModel.CollectionItem) error {
+		item := findItem(items, itemUUID)
+		if item == nil {
+			return common.ErrCollectionItemNotFound
+		}
+
+		startTime, endTime := item.StartTime, item.EndTime
+		if req.WholeVideo {
+			startTime, endTime = nil, nil
+		}
+		if req.StartTime != nil {
+			startTime = req.StartTime
+		}
+		if req.EndTime != nil {
+			endTime = req.EndTime
+		}
+		if err := validateRange(startTime, endTime); err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"start_time": startTime, "end_time": endTime}
+		if req.Note != nil {
+			columns["note"] = *req.Note
+		}
+		_, err := collectionRepo.NewItemRepository(tx).UpdateColumns(s.sc.Ctx(), itemUUID, columns)
+		return err
+	})
+}
+
+func (s *collectionService) RemoveItem(id string, itemID string, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	return s.writeItems(id, viewer, func(tx *gorm.DB, c *collectionModel.Collection, items []collectionModel.CollectionItem) error {
+		if findItem(items, itemUUID) == nil {
+			return common.ErrCollectionItemNotFound
+		}
+
+		itemRepo := collectionRepo.NewItemRepository(tx)
+		if err := itemRepo.Delete(s.sc.Ctx(), func(tx *gorm.DB) {
+			tx.Where("id = ?", itemUUID)
+		}); err != nil {
+			return err
+		}
+
+		remaining := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			if item.ID != itemUUID {
+				remaining = append(remaining, item.ID)
+			}
+		}
+		return itemRepo.SetPositions(s.sc.Ctx(), c.ID, remaining)
+	})
+}
+
+// ReorderItems sets the order of the items; every item must be listed once.
+func (s *collectionService) ReorderItems(id string, req collectionModel.ReorderCollectionItemsRequest, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	return s.writeItems(id, viewer, func(tx *gorm.DB, c *collectionModel.Collection, items []collectionModel.CollectionItem) error {
+		if len(req.ItemIDs) != len(items) {
+			return common.ErrInvalidCollectionOrder
+		}
+		seen := make(map[uuid.UUID]bool, len(req.ItemIDs))
+		for _, itemID := range req.ItemIDs {
+			if seen[itemID] || findItem(items, itemID) == nil {
+				return common.ErrInvalidCollectionOrder
+			}
+			seen[itemID] = true
+		}
+		return collectionRepo.NewItemRepository(tx).SetPositions(s.sc.Ctx(), c.ID, req.ItemIDs)
+	})
+}
+
+// writeItems runs write on the items of a collection the viewer can edit, in
+// a transaction holding the collection row lock, and returns the updated
+// collection.
+func (s *collectionService) writeItems(id string, viewer common.Viewer, write func(tx *gorm.DB, c *collectionModel.Collection, items []collectionModel.CollectionItem) error) (*collectionModel.CollectionResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	var updated *collectionModel.Collection
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		c, err := authorizeCollection(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessEditor, true)
+		if err != nil {
+			return err
+		}
+		items, err := collectionRepo.NewItemRepository(tx).ListByCollectionID(s.sc.Ctx(), uuidID)
+		if err != nil {
+			return err
+		}
+		if err := write(tx, c, items); err != nil {
+			return err
+		}
+		updated, err = collectionRepo.NewRepository(tx).UpdateColumns(s.sc.Ctx(), uuidID, map[string]interface{}{
+			"updated_by": viewer.UserID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(s.sc.DB(), updated, viewer)
+}
+
+// insertItems validates inputs and inserts them at position, shifting the
+// following items.
+func (s *collectionService) insertItems(tx *gorm.DB, collectionID uuid.UUID, position int, inputs []collectionModel.CollectionItemInput, viewer common.Viewer) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+	if err := s.validateItems(tx, inputs, viewer); err != nil {
+		return err
+	}
+
+	itemRepo := collectionRepo.NewItemRepository(tx)
+	existing, err := itemRepo.ListByCollectionID(s.sc.Ctx(), collectionID)
+	if err != nil {
+		return err
+	}
+
+	// New items are appended, then moved into place if needed.
+	added := make([]*collectionModel.CollectionItem, 0, len(inputs))
+	for i, input := range inputs {
+		added = append(added, &collectionModel.CollectionItem{
+			ID:           uuid.New(),
+			CollectionID: collectionID,
+			Position:     len(existing) + i,
+			VideoID:      input.VideoID,
+			StartTime:    input.StartTime,
+			EndTime:      input.EndTime,
+			Note:         input.Note,
+			CreatedBy:    viewer.UserID,
+		})
+	}
+	if err := itemRepo.CreatesMultiple(s.sc.Ctx(), added); err != nil {
+		return err
+	}
+	if position >= len(existing) {
+		return nil
+	}
+
+	order := make([]uuid.UUID, 0, len(existing)+len(added))
+	for _, item := range existing[:position] {
+		order = append(order, item.ID)
+	}
+	for _, item := range added {
+		order = append(order, item.ID)
+	}
+	for _, item := range existing[position:] {
+		order = append(order, item.ID)
+	}
+	return itemRepo.SetPositions(s.sc.Ctx(), collectionID, order)
+}
+
+// validateItems checks the scene ranges and that every referenced video is
+// visible to the viewer.
+func (s *collectionService) validateItems(tx *gorm.DB, inputs []collectionModel.CollectionItemInput, viewer common.Viewer) error {
+	videoIDs := make([]uuid.UUID, 0, len(inputs))
+	seen := make(map[uuid.UUID]bool, len(inputs))
+	for _, input := range inputs {
+		if err := validateRange(input.StartTime, input.EndTime); err != nil {
+			return err
+		}
+		if !seen[input.VideoID] {
+			seen[input.VideoID] = true
+			videoIDs = append(videoIDs, input.VideoID)
+		}
+	}
+
+	visible, err := videoRepo.NewRepository(tx).List(s.sc.Ctx(), models.QueryParams{Selected: []string{"id"}},
+		videoRepo.VisibleTo(viewer),
+		func(q *gorm.DB) {
+			q.Where("videos.id IN ?", videoIDs)
+		})
+	if err != nil {
+		return err
+	}
+	found := make(map[uuid.UUID]bool, len(visible))
+	for _, v := range visible {
+		found[v.ID] = true
+	}
+	for _, videoID := range videoIDs {
+		if !found[videoID] {
+			return fmt.Errorf("%w: video %s not found", common.ErrInvalidCollectionItem, videoID)
+		}
+	}
+	return nil
+}
+
+func validateRange(startTime, endTime *float64) error {
+	if (startTime == nil) != (endTime == nil) {
+		return fmt.Errorf("%w: a scene needs both start_time and end_time", common.ErrInvalidCollectionItem)
+	}
+	if startTime != nil && *startTime >= *endTime {
+		return fmt.Errorf("%w: start_time must be before end_time", common.ErrInvalidCollectionItem)
+	}
+	return nil
+}
+
+func findItem(items []collectionModel.CollectionItem, id uuid.UUID) *collectionModel.CollectionItem {
+	for i := range items {
+		if items[i].ID == id {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// authorizeCollection loads a collection, optionally locking it, and checks
+// that viewer has at least level on it. Collections the viewer cannot see are
+// reported as not found.
+func authorizeCollection(ctx context.Context, db *gorm.DB, id uuid.UUID, viewer common.Viewer, level videoModel.AccessLevel, forUpdate bool) (*collectionModel.Collection, error) {
+	repo := collectionRepo.NewRepository(db)
+	var (
+		c   *collectionModel.Collection
+		err error
+	)
+	if forUpdate {
+		c, err = repo.GetByIDForUpdate(ctx, id)
+	} else {
+		c, err = repo.GetByID(ctx, id)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCollectionNotFound
+		}
+		return nil, err
+	}
+
+	var grants []collectionModel.CollectionShare
+	if !viewer.Admin && c.CreatedBy != viewer.UserID && c.Visibility != videoModel.VisibilityPrivate {
+		grants, err = collectionRepo.NewShareRepository(db).ListGrants(ctx, id, viewer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	access := c.AccessFor(viewer, grants)
+	switch {
+	case access == videoModel.AccessNone:
+		return nil, common.ErrCollectionNotFound
+	case access < level:
+		return nil, common.ErrCollectionForbidden
+	}
+	return c, nil
+}
+
+// detail returns a collection with its items. Items whose video was deleted,
+// or is not visible to the viewer, are flagged as stale.
+func (s *collectionService) detail(db *gorm.DB, c *collectionModel.Collection, viewer common.Viewer) (*collectionModel.CollectionResponse, error) {
+	ctx := s.sc.Ctx()
+	items, err := collectionRepo.NewItemRepository(db).ListByCollectionID(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	videoIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		videoIDs = append(videoIDs, item.VideoID)
+	}
+	repo := videoRepo.NewRepository(db)
+	videos, err := repo.ListByIDsWithTrashed(ctx, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	videosByID := make(map[uuid.UUID]*videoModel.Video, len(videos))
+	for _, v := range videos {
+		videosByID[v.ID] = v
+	}
+
+	visibleIDs := make(map[uuid.UUID]bool, len(videos))
+	if len(videoIDs) > 0 {
+		visible, err := repo.List(ctx, models.QueryParams{Selected: []string{"id"}},
+			videoRepo.VisibleTo(viewer),
+			func(tx *gorm.DB) {
+				tx.Where("videos.id IN ?", videoIDs)
+			})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range visible {
+			visibleIDs[v.ID] = true
+		}
+	}
+
+	response := toCollectionResponse(c)
+	response.ItemCount = len(items)
+	response.Items = make([]collectionModel.CollectionItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponse := collectionModel.CollectionItemResponse{
+			ID:        item.ID,
+			Position:  item.Position,
+			VideoID:   item.VideoID,
+			StartTime: item.StartTime,
+			EndTime:   item.EndTime,
+			Note:      item.Note,
+			CreatedBy: item.CreatedBy,
+			CreatedAt: item.CreatedAt,
+		}
+
+		v := videosByID[item.VideoID]
+		switch {
+		case v == nil || v.DeletedAt.Valid:
+			itemResponse.Stale = true
+			itemResponse.Warning = collectionModel.ItemWarningVideoDeleted
+		case !visibleIDs[item.VideoID]:
+			itemResponse.Stale = true
+			itemResponse.Warning = collectionModel.ItemWarningVideoUnavailable
+		default:
+			itemResponse.Video = &collectionModel.CollectionItemVideo{
+				Title:        v.Title,
+				ThumbnailURL: v.ThumbnailURL,
+				Duration:     v.Duration,
+				Status:       v.Status,
+			}
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+	return &response, nil
+}
+
+func toCollectionResponse(c *collectionModel.Collection) collectionModel.CollectionResponse {
+	return collectionModel.CollectionResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Visibility:  c.Visibility,
+		CreatedBy:   c.CreatedBy,
+		UpdatedBy:   c.UpdatedBy,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
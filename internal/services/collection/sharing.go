@@ -0,0 +1,134 @@
+package collection
+
+import (
+	"errors"
+	"smart-scene-app-api/common"
+	collectionModel "smart-scene-app-api/internal/models/collection"
+	videoModel "smart-scene-app-api/internal/models/video"
+	collectionRepo "smart-scene-app-api/internal/repositories/collection"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCollectionSharing returns the visibility and shares of a collection.
+// Only the owner can see who a collection is shared with.
+func (s *collectionService) GetCollectionSharing(id string, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	c, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(c)
+}
+
+func (s *collectionService) SetCollectionVisibility(id string, req videoModel.VideoVisibilityRequest, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if !videoModel.IsValidVisibility(req.Visibility) {
+		return nil, common.ErrInvalidVisibility
+	}
+	if _, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner, false); err != nil {
+		return nil, err
+	}
+
+	c, err := s.collectionRepo.UpdateColumns(s.sc.Ctx(), uuidID, map[string]interface{}{
+		"visibility": req.Visibility,
+		"updated_by": viewer.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(c)
+}
+
+// ShareCollection grants a user or a role access to a collection. Sharing
+// again with the same user or role changes the permission of the existing
+// share. Videos in the collection keep their own visibility.
+func (s *collectionService) ShareCollection(id string, req videoModel.VideoShareRequest, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if (req.UserID == nil) == (req.RoleID == nil) {
+		return nil, common.ErrInvalidVideoShare
+	}
+
+	var c *collectionModel.Collection
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		if c, err = authorizeCollection(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessOwner, true); err != nil {
+			return err
+		}
+
+		shareRepo := collectionRepo.NewShareRepository(tx)
+		existing, err := shareRepo.FindByGrantee(s.sc.Ctx(), uuidID, req.UserID, req.RoleID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			_, err = shareRepo.UpdateColumns(s.sc.Ctx(), existing.ID, map[string]interface{}{"permission": req.Permission})
+			return err
+		}
+		_, err = shareRepo.Create(s.sc.Ctx(), &collectionModel.CollectionShare{
+			CollectionID: uuidID,
+			UserID:       req.UserID,
+			RoleID:       req.RoleID,
+			Permission:   req.Permission,
+			CreatedBy:    viewer.UserID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(c)
+}
+
+func (s *collectionService) UnshareCollection(id string, shareID string, viewer common.Viewer) (*collectionModel.CollectionSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	shareUUID, err := uuid.Parse(shareID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	c, err := authorizeCollection(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ofCollection := func(tx *gorm.DB) {
+		tx.Where("id = ? AND collection_id = ?", shareUUID, uuidID)
+	}
+	if _, err := s.shareRepo.GetDetailByConditions(s.sc.Ctx(), ofCollection); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCollectionShareNotFound
+		}
+		return nil, err
+	}
+	if err := s.shareRepo.Delete(s.sc.Ctx(), ofCollection); err != nil {
+		return nil, err
+	}
+	return s.sharingOf(c)
+}
+
+func (s *collectionService) sharingOf(c *collectionModel.Collection) (*collectionModel.CollectionSharingResponse, error) {
+	shares, err := s.shareRepo.ListByCollectionID(s.sc.Ctx(), c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if shares == nil {
+		shares = []collectionModel.CollectionShare{}
+	}
+	return &collectionModel.CollectionSharingResponse{
+		CollectionID: c.ID,
+		Visibility:   c.Visibility,
+		Shares:       shares,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/config"
+	jobModel "smart-scene-app-api/internal/models/job"
+	"smart-scene-app-api/pkg/redis"
+	"smart-scene-app-api/server"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	EnqueueVideoAnalysis(videoID uuid.UUID) error
+}
+
+type jobService struct {
+	sc    server.ServerContext
+	redis redis.ClientI
+}
+
+func NewJobService(sc server.ServerContext) Service {
+	return &jobService{
+		sc:    sc,
+		redis: sc.Redis(),
+	}
+}
+
+// VideoAnalysisQueue opens the analysis queue with the configured worker options.
+func VideoAnalysisQueue(client redis.ClientI) *redis.Queue {
+	cfg := config.Config.Worker
+	return client.Queue(common.QUEUE_VIDEO_ANALYSIS, redis.QueueOptions{
+		VisibilityTimeout: time.Duration(cfg.VisibilityTimeout) * time.Second,
+		MaxAttempts:       cfg.MaxAttempts,
+		RetryBackoff:      time.Duration(cfg.RetryBackoff) * time.Second,
+	})
+}
+
+func (s *jobService) EnqueueVideoAnalysis(videoID uuid.UUID) error {
+	if s.redis == nil {
+		return fmt.Errorf("redis is not available")
+	}
+	_, err := VideoAnalysisQueue(s.redis).Enqueue(s.sc.Ctx(), common.JOB_TYPE_VIDEO_ANALYSIS,
+		jobModel.VideoAnalysisJobID(videoID), jobModel.VideoAnalysisPayload{VideoID: videoID})
+	return err
+}
@@ -2,17 +2,23 @@ package tag
 
 import (
 	"context"
+	"smart-scene-app-api/common"
 	"smart-scene-app-api/internal/models"
 	tagModels "smart-scene-app-api/internal/models/tag"
 	tagRepo "smart-scene-app-api/internal/repositories/tag"
 	"smart-scene-app-api/server"
 	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Service interface {
 	GetTagsByPosition(ctx context.Context, req tagModels.TagFilterRequest) (*tagModels.TagListResponse, error)
+	GetVideoTags(ctx context.Context, videoID string, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error)
+	AttachVideoTags(ctx context.Context, videoID string, req tagModels.VideoTagsRequest, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error)
+	ReplaceVideoTags(ctx context.Context, videoID string, req tagModels.VideoTagsRequest, ifMatch int, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error)
+	DetachVideoTag(ctx context.Context, videoID string, tagID int, characterID *uuid.UUID, ifMatch int, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error)
 }
 
 type tagService struct {
@@ -150,6 +156,8 @@ func (s *tagService) GetTagsByPosition(ctx context.Context, req tagModels.TagFil
 				Color:      color,
 				UsageCount: tag.UsageCount,
 				IsActive:   tag.IsActive,
+				RangeMin:   tag.RangeMin,
+				RangeMax:   tag.RangeMax,
 			})
 		}
 
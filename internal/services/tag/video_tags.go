@@ -0,0 +1,282 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/internal/models"
+	characterModel "smart-scene-app-api/internal/models/character"
+	tagModels "smart-scene-app-api/internal/models/tag"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+	tagRepo "smart-scene-app-api/internal/repositories/tag"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	videoService "smart-scene-app-api/internal/services/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type videoTagKey struct {
+	tagID       int
+	characterID uuid.UUID
+}
+
+func keyOf(tagID int, characterID *uuid.UUID) videoTagKey {
+	key := videoTagKey{tagID: tagID}
+	if characterID != nil {
+		key.characterID = *characterID
+	}
+	return key
+}
+
+// GetVideoTags returns the tags of a video and the version of the video.
+func (s *tagService) GetVideoTags(ctx context.Context, videoID string, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, 0, common.ErrInvalidUUID
+	}
+
+	video, err := videoService.AuthorizeVideo(ctx, s.db, uuidID, viewer, videoModel.AccessViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	videoTags, err := tagRepo.NewVideoTagRepository(s.db).ListByVideoID(ctx, uuidID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toVideoTagResponses(videoTags), video.Version, nil
+}
+
+// AttachVideoTags adds tags to a video. Tags that are already attached are
+// left as they are.
+func (s *tagService) AttachVideoTags(ctx context.Context, videoID string, req tagModels.VideoTagsRequest, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error) {
+	return s.writeVideoTags(ctx, videoID, 0, viewer, func(existing []tagModels.VideoTag) ([]tagModels.VideoTagInput, error) {
+		final := make([]tagModels.VideoTagInput, 0, len(existing)+len(req.Tags))
+		for _, vt := range existing {
+			final = append(final, tagModels.VideoTagInput{TagID: vt.TagID, CharacterID: vt.CharacterID})
+		}
+		return append(final, req.Tags...), nil
+	})
+}
+
+// ReplaceVideoTags sets the exact tag list of a video.
+func (s *tagService) ReplaceVideoTags(ctx context.Context, videoID string, req tagModels.VideoTagsRequest, ifMatch int, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error) {
+	return s.writeVideoTags(ctx, videoID, ifMatch, viewer, func(existing []tagModels.VideoTag) ([]tagModels.VideoTagInput, error) {
+		return req.Tags, nil
+	})
+}
+
+// DetachVideoTag removes a tag from a video. When characterID is nil every
+// attachment of the tag is removed, otherwise only the one for that character.
+func (s *tagService) DetachVideoTag(ctx context.Context, videoID string, tagID int, characterID *uuid.UUID, ifMatch int, viewer common.Viewer) ([]tagModels.VideoTagResponse, int, error) {
+	return s.writeVideoTags(ctx, videoID, ifMatch, viewer, func(existing []tagModels.VideoTag) ([]tagModels.VideoTagInput, error) {
+		final := make([]tagModels.VideoTagInput, 0, len(existing))
+		removed := false
+		for _, vt := range existing {
+			matches := vt.TagID == tagID && (characterID == nil || keyOf(vt.TagID, vt.CharacterID) == keyOf(tagID, characterID))
+			if matches {
+				removed = true
+				continue
+			}
+			final = append(final, tagModels.VideoTagInput{TagID: vt.TagID, CharacterID: vt.CharacterID})
+		}
+		if !removed {
+			return nil, common.ErrVideoTagNotFound
+		}
+		return final, nil
+	})
+}
+
+// writeVideoTags computes the desired tag list of a video from its current
+// one and applies the difference in a single transaction, keeping
+// tags.usage_count in step. The video row is locked so that concurrent writes
+// to the same video cannot break the single-tag rule of a category. viewer
+// needs edit access to the video; a change increments its version, which a
+// non-zero ifMatch must match. The version of the video after the write is
+// returned with its tags.
+func (s *tagService) writeVideoTags(ctx context.Context, videoID string, ifMatch int, viewer common.Viewer, desired func(existing []tagModels.VideoTag) ([]tagModels.VideoTagInput, error)) ([]tagModels.VideoTagResponse, int, error) {
+	uuidID, err := uuid.Parse(videoID)
+	if err != nil {
+		return nil, 0, common.ErrInvalidUUID
+	}
+
+	var result []tagModels.VideoTag
+	var version int
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		video, err := videoService.LockVideo(ctx, tx, uuidID, viewer, videoModel.AccessEditor, ifMatch)
+		if err != nil {
+			return err
+		}
+		version = video.Version
+
+		videoTagRepo := tagRepo.NewVideoTagRepository(tx)
+		existing, err := videoTagRepo.ListByVideoID(ctx, uuidID)
+		if err != nil {
+			return err
+		}
+
+		inputs, err := desired(existing)
+		if err != nil {
+			return err
+		}
+
+		existingKeys := make(map[videoTagKey]bool, len(existing))
+		for _, vt := range existing {
+			existingKeys[keyOf(vt.TagID, vt.CharacterID)] = true
+		}
+
+		wanted := make(map[videoTagKey]bool, len(inputs))
+		var additions []*tagModels.VideoTag
+		for _, input := range inputs {
+			key := keyOf(input.TagID, input.CharacterID)
+			if wanted[key] {
+				continue
+			}
+			wanted[key] = true
+			if !existingKeys[key] {
+				additions = append(additions, &tagModels.VideoTag{VideoID: uuidID, TagID: input.TagID, CharacterID: input.CharacterID})
+			}
+		}
+
+		if err := s.validateVideoTags(ctx, tx, existing, additions, wanted); err != nil {
+			return err
+		}
+
+		deltas := make(map[int]int)
+		var removals []int
+		for _, vt := range existing {
+			if !wanted[keyOf(vt.TagID, vt.CharacterID)] {
+				removals = append(removals, vt.ID)
+				deltas[vt.TagID]--
+			}
+		}
+		for _, vt := range additions {
+			deltas[vt.TagID]++
+		}
+
+		if len(removals) > 0 {
+			if err := videoTagRepo.Delete(ctx, func(tx *gorm.DB) {
+				tx.Where("id IN ?", removals)
+			}); err != nil {
+				return err
+			}
+		}
+		if len(additions) > 0 {
+			if err := videoTagRepo.CreatesMultiple(ctx, additions); err != nil {
+				return err
+			}
+		}
+		if err := tagRepo.NewTagMainRepository(tx).AdjustUsageCounts(ctx, deltas); err != nil {
+			return err
+		}
+		if len(removals) > 0 || len(additions) > 0 {
+			updated, err := videoRepo.NewRepository(tx).UpdateColumns(ctx, uuidID, map[string]interface{}{"updated_by": viewer.UserID})
+			if err != nil {
+				return err
+			}
+			version = updated.Version
+		}
+
+		result, err = videoTagRepo.ListByVideoID(ctx, uuidID)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return toVideoTagResponses(result), version, nil
+}
+
+// validateVideoTags checks that added tags are active, that their characters
+// exist, and that the resulting list has at most one tag per single category.
+func (s *tagService) validateVideoTags(ctx context.Context, tx *gorm.DB, existing []tagModels.VideoTag, additions []*tagModels.VideoTag, wanted map[videoTagKey]bool) error {
+	tags := make(map[int]tagModels.Tag, len(existing))
+	for _, vt := range existing {
+		tags[vt.TagID] = vt.Tag
+	}
+
+	var newTagIDs []int
+	characterIDs := make(map[uuid.UUID]bool)
+	for _, vt := range additions {
+		if _, ok := tags[vt.TagID]; !ok {
+			newTagIDs = append(newTagIDs, vt.TagID)
+		}
+		if vt.CharacterID != nil {
+			characterIDs[*vt.CharacterID] = true
+		}
+	}
+
+	if len(newTagIDs) > 0 {
+		loaded, err := tagRepo.NewTagMainRepository(tx).List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Preload("Category").Where("id IN ?", newTagIDs)
+		})
+		if err != nil {
+			return err
+		}
+		for _, t := range loaded {
+			tags[t.ID] = *t
+		}
+	}
+	for _, vt := range additions {
+		t, ok := tags[vt.TagID]
+		if !ok {
+			return fmt.Errorf("%w: %d", common.ErrTagNotFound, vt.TagID)
+		}
+		if !t.IsActive {
+			return fmt.Errorf("%w: %s", common.ErrTagInactive, t.Code)
+		}
+	}
+
+	if len(characterIDs) > 0 {
+		ids := make([]uuid.UUID, 0, len(characterIDs))
+		for id := range characterIDs {
+			ids = append(ids, id)
+		}
+		count, err := repositories.NewBaseRepository[characterModel.Character](tx).Count(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ?", ids)
+		})
+		if err != nil {
+			return err
+		}
+		if int(count) != len(ids) {
+			return common.ErrCharacterNotFound
+		}
+	}
+
+	singleTags := make(map[int]int)
+	for key := range wanted {
+		t := tags[key.tagID]
+		if t.Category.FilterType != tagModels.FilterTypeSingle {
+			continue
+		}
+		if other, ok := singleTags[t.CategoryID]; ok && other != t.ID {
+			return fmt.Errorf("%w: %s", common.ErrTagCategorySingle, t.Category.Code)
+		}
+		singleTags[t.CategoryID] = t.ID
+	}
+	return nil
+}
+
+func toVideoTagResponses(videoTags []tagModels.VideoTag) []tagModels.VideoTagResponse {
+	responses := make([]tagModels.VideoTagResponse, 0, len(videoTags))
+	for _, vt := range videoTags {
+		color := vt.Tag.Color
+		if color == "" {
+			color = vt.Tag.Category.Color
+		}
+		responses = append(responses, tagModels.VideoTagResponse{
+			ID:           vt.ID,
+			TagID:        vt.TagID,
+			TagName:      vt.Tag.Name,
+			TagCode:      vt.Tag.Code,
+			TagColor:     color,
+			CategoryID:   vt.Tag.CategoryID,
+			CategoryName: vt.Tag.Category.Name,
+			CategoryCode: vt.Tag.Category.Code,
+			FilterType:   vt.Tag.Category.FilterType,
+			CharacterID:  vt.CharacterID,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,358 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"smart-scene-app-api/common"
+	uploadModel "smart-scene-app-api/internal/models/upload"
+	videoModel "smart-scene-app-api/internal/models/video"
+	uploadRepo "smart-scene-app-api/internal/repositories/upload"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/pkg/storage"
+	"smart-scene-app-api/server"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	InitiateUpload(req uploadModel.InitiateUploadRequest, userID uuid.UUID) (*uploadModel.UploadSession, error)
+	UploadPart(id string, partNumber int, body io.Reader, checksum string, userID uuid.UUID) (*uploadModel.UploadPart, error)
+	GetUploadStatus(id string, userID uuid.UUID) (*uploadModel.UploadStatusResponse, error)
+	CompleteUpload(id string, userID uuid.UUID) (*videoModel.Video, error)
+	AbortUpload(id string, userID uuid.UUID) error
+}
+
+type uploadService struct {
+	sc           server.ServerContext
+	uploadRepo   uploadRepo.Repository
+	partRepo     uploadRepo.PartRepository
+	videoService videoService.Service
+	storage      storage.ObjectStorage
+}
+
+func NewUploadService(sc server.ServerContext) Service {
+	return &uploadService{
+		sc:           sc,
+		uploadRepo:   uploadRepo.NewRepository(sc.DB()),
+		partRepo:     uploadRepo.NewPartRepository(sc.DB()),
+		videoService: videoService.NewVideoService(sc),
+		storage:      sc.Storage(),
+	}
+}
+
+func (s *uploadService) InitiateUpload(req uploadModel.InitiateUploadRequest, userID uuid.UUID) (*uploadModel.UploadSession, error) {
+	chunkSize := req.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = uploadModel.DefaultChunkSize
+	}
+	if chunkSize < uploadModel.MinChunkSize || chunkSize > uploadModel.MaxChunkSize {
+		return nil, fmt.Errorf("%w: chunk_size must be between %d and %d bytes", common.ErrInvalidUploadPart, uploadModel.MinChunkSize, uploadModel.MaxChunkSize)
+	}
+
+	session := &uploadModel.UploadSession{
+		ID:          uuid.New(),
+		CreatedBy:   userID,
+		Status:      uploadModel.UploadStatusUploading,
+		Title:       req.Title,
+		FileName:    sanitizeFileName(req.FileName),
+		ContentType: req.ContentType,
+		TotalSize:   req.TotalSize,
+		ChunkSize:   chunkSize,
+		TotalChunks: int((req.TotalSize + chunkSize - 1) / chunkSize),
+		Duration:    req.Duration,
+		Metadata:    req.Metadata,
+		ExpiresAt:   time.Now().Add(uploadModel.SessionLifetime),
+	}
+
+	return s.uploadRepo.Create(s.sc.Ctx(), session)
+}
+
+func (s *uploadService) UploadPart(id string, partNumber int, body io.Reader, checksum string, userID uuid.UUID) (*uploadModel.UploadPart, error) {
+	session, err := s.getActiveSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if partNumber < 1 || partNumber > session.TotalChunks {
+		return nil, fmt.Errorf("%w: part_number must be between 1 and %d", common.ErrInvalidUploadPart, session.TotalChunks)
+	}
+
+	expected := session.ExpectedPartSize(partNumber)
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	// Read one byte more than expected so oversized chunks are detected.
+	reader := io.TeeReader(io.LimitReader(body, expected+1), io.MultiWriter(hasher, counter))
+
+	staging := session.StagingPartKey(partNumber, uuid.New())
+	defer func() { _ = s.storage.DeleteObject(s.sc.Ctx(), staging) }()
+	if err := s.storage.PutObject(s.sc.Ctx(), staging, reader, "application/octet-stream"); err != nil {
+		return nil, err
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if counter.n != expected {
+		return nil, fmt.Errorf("%w: part %d must be %d bytes, got %d", common.ErrInvalidUploadPart, partNumber, expected, counter.n)
+	}
+	if checksum != "" && !strings.EqualFold(checksum, sum) {
+		return nil, common.ErrUploadChecksumMismatch
+	}
+
+	key := session.PartKey(partNumber)
+	if err := s.copyObject(staging, key); err != nil {
+		// A failed copy may have left a previously accepted part truncated.
+		_ = s.storage.DeleteObject(s.sc.Ctx(), key)
+		if derr := s.partRepo.DeletePart(s.sc.Ctx(), session.ID, partNumber); derr != nil {
+			return nil, derr
+		}
+		return nil, err
+	}
+
+	part := &uploadModel.UploadPart{
+		CreatedAt:  time.Now(),
+		UploadID:   session.ID,
+		PartNumber: partNumber,
+		Size:       counter.n,
+		Checksum:   sum,
+	}
+	if err := s.partRepo.Upsert(s.sc.Ctx(), part); err != nil {
+		return nil, err
+	}
+	return part, nil
+}
+
+func (s *uploadService) GetUploadStatus(id string, userID uuid.UUID) (*uploadModel.UploadStatusResponse, error) {
+	session, err := s.getSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.partRepo.ListByUploadID(s.sc.Ctx(), session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	received := make(map[int]bool, len(parts))
+	var receivedBytes int64
+	for _, p := range parts {
+		received[p.PartNumber] = true
+		receivedBytes += p.Size
+	}
+
+	var offset int64
+	contiguous := true
+	missing := []int{}
+	for n := 1; n <= session.TotalChunks; n++ {
+		if !received[n] {
+			missing = append(missing, n)
+			contiguous = false
+			continue
+		}
+		if contiguous {
+			offset += session.ExpectedPartSize(n)
+		}
+	}
+
+	if parts == nil {
+		parts = []uploadModel.UploadPart{}
+	}
+	return &uploadModel.UploadStatusResponse{
+		UploadSession:  *session,
+		ReceivedOffset: offset,
+		ReceivedBytes:  receivedBytes,
+		ReceivedParts:  parts,
+		MissingParts:   missing,
+	}, nil
+}
+
+func (s *uploadService) CompleteUpload(id string, userID uuid.UUID) (*videoModel.Video, error) {
+	session, err := s.getActiveSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.partRepo.ListByUploadID(s.sc.Ctx(), session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != session.TotalChunks {
+		return nil, common.ErrUploadIncomplete
+	}
+	for i, p := range parts {
+		if p.PartNumber != i+1 || p.Size != session.ExpectedPartSize(p.PartNumber) {
+			return nil, common.ErrUploadIncomplete
+		}
+	}
+
+	won, err := s.uploadRepo.CompareAndSetStatus(s.sc.Ctx(), session.ID, uploadModel.UploadStatusUploading, uploadModel.UploadStatusAssembling)
+	if err != nil {
+		return nil, err
+	}
+	if !won {
+		return nil, common.ErrUploadNotActive
+	}
+
+	createdVideo, err := s.assemble(session, parts)
+	if err != nil {
+		// Give the client a chance to retry the completion.
+		_, _ = s.uploadRepo.CompareAndSetStatus(s.sc.Ctx(), session.ID, uploadModel.UploadStatusAssembling, uploadModel.UploadStatusUploading)
+		return nil, err
+	}
+
+	for _, p := range parts {
+		_ = s.storage.DeleteObject(s.sc.Ctx(), session.PartKey(p.PartNumber))
+	}
+	return createdVideo, nil
+}
+
+func (s *uploadService) AbortUpload(id string, userID uuid.UUID) error {
+	session, err := s.getActiveSession(id, userID)
+	if err != nil {
+		return err
+	}
+
+	won, err := s.uploadRepo.CompareAndSetStatus(s.sc.Ctx(), session.ID, uploadModel.UploadStatusUploading, uploadModel.UploadStatusAborted)
+	if err != nil {
+		return err
+	}
+	if !won {
+		return common.ErrUploadNotActive
+	}
+
+	parts, err := s.partRepo.ListByUploadID(s.sc.Ctx(), session.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		_ = s.storage.DeleteObject(s.sc.Ctx(), session.PartKey(p.PartNumber))
+	}
+	return nil
+}
+
+// assemble streams every part, in order, into the final object and creates the video row.
+func (s *uploadService) assemble(session *uploadModel.UploadSession, parts []uploadModel.UploadPart) (*videoModel.Video, error) {
+	finalKey := "videos/" + session.ID.String() + "/" + session.FileName
+	hasher := sha256.New()
+	counter := &countingWriter{}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for _, p := range parts {
+			body, err := s.storage.GetObject(s.sc.Ctx(), session.PartKey(p.PartNumber))
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			_, err = io.Copy(pw, body)
+			body.Close()
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	reader := io.TeeReader(pr, io.MultiWriter(hasher, counter))
+	if err := s.storage.PutObject(s.sc.Ctx(), finalKey, reader, session.ContentType); err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	if counter.n != session.TotalSize {
+		_ = s.storage.DeleteObject(s.sc.Ctx(), finalKey)
+		return nil, common.ErrUploadIncomplete
+	}
+
+	metadata := common.JSON{}
+	for k, v := range session.Metadata {
+		metadata[k] = v
+	}
+	metadata["size"] = counter.n
+	metadata["checksum_sha256"] = hex.EncodeToString(hasher.Sum(nil))
+	metadata["original_file_name"] = session.FileName
+	metadata["content_type"] = session.ContentType
+	metadata["upload_id"] = session.ID.String()
+
+	// The session is completed in the transaction creating the video, so that
+	// a retried completion never creates a second video.
+	createdVideo, err := s.videoService.CreateVideoWith(videoModel.Video{
+		Title:     session.Title,
+		FilePath:  finalKey,
+		Duration:  session.Duration,
+		Metadata:  metadata,
+		CreatedBy: session.CreatedBy,
+		UpdatedBy: session.CreatedBy,
+	}, func(tx *gorm.DB, created *videoModel.Video) error {
+		_, err := uploadRepo.NewRepository(tx).UpdateColumns(s.sc.Ctx(), session.ID, map[string]interface{}{
+			"status":   uploadModel.UploadStatusCompleted,
+			"video_id": created.ID,
+		})
+		return err
+	})
+	if err != nil {
+		_ = s.storage.DeleteObject(s.sc.Ctx(), finalKey)
+		return nil, err
+	}
+	return createdVideo, nil
+}
+
+func (s *uploadService) copyObject(from, to string) error {
+	body, err := s.storage.GetObject(s.sc.Ctx(), from)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return s.storage.PutObject(s.sc.Ctx(), to, body, "application/octet-stream")
+}
+
+func (s *uploadService) getSession(id string, userID uuid.UUID) (*uploadModel.UploadSession, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	session, err := s.uploadRepo.GetByID(s.sc.Ctx(), uuidID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrUploadNotFound
+		}
+		return nil, err
+	}
+	if session.CreatedBy != userID {
+		return nil, common.ErrUploadNotFound
+	}
+	return session, nil
+}
+
+func (s *uploadService) getActiveSession(id string, userID uuid.UUID) (*uploadModel.UploadSession, error) {
+	session, err := s.getSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != uploadModel.UploadStatusUploading || time.Now().After(session.ExpiresAt) {
+		return nil, common.ErrUploadNotActive
+	}
+	return session, nil
+}
+
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." || name == "" {
+		return "source"
+	}
+	return name
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
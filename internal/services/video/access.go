@@ -0,0 +1,72 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"smart-scene-app-api/common"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthorizeVideo loads a video and checks that viewer has at least level on
+// it. Videos the viewer cannot see are reported as not found so that their
+// existence is not disclosed.
+func AuthorizeVideo(ctx context.Context, db *gorm.DB, videoID uuid.UUID, viewer common.Viewer, level videoModel.AccessLevel) (*videoModel.Video, error) {
+	v, err := video.NewRepository(db).GetByID(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrVideoNotFound
+		}
+		return nil, err
+	}
+	if err := CheckVideoAccess(ctx, db, v, viewer, level); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CheckVideoAccess checks that viewer has at least level on an already loaded
+// video, returning ErrVideoNotFound when the video is not visible to them and
+// ErrVideoForbidden when it is visible but the level is not reached.
+func CheckVideoAccess(ctx context.Context, db *gorm.DB, v *videoModel.Video, viewer common.Viewer, level videoModel.AccessLevel) error {
+	var grants []videoModel.VideoShare
+	if !viewer.Admin && v.CreatedBy != viewer.UserID && v.Visibility != videoModel.VisibilityPrivate {
+		var err error
+		grants, err = video.NewShareRepository(db).ListGrants(ctx, v.ID, viewer)
+		if err != nil {
+			return err
+		}
+	}
+
+	access := v.AccessFor(viewer, grants)
+	switch {
+	case access == videoModel.AccessNone:
+		return common.ErrVideoNotFound
+	case access < level:
+		return common.ErrVideoForbidden
+	}
+	return nil
+}
+
+// LockVideo loads a video with SELECT ... FOR UPDATE in tx and checks that
+// viewer has at least level on it and, when ifMatch is set, that it is still
+// at that version.
+func LockVideo(ctx context.Context, tx *gorm.DB, videoID uuid.UUID, viewer common.Viewer, level videoModel.AccessLevel, ifMatch int) (*videoModel.Video, error) {
+	v, err := video.NewRepository(tx).GetByIDForUpdate(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrVideoNotFound
+		}
+		return nil, err
+	}
+	if err := CheckVideoAccess(ctx, tx, v, viewer, level); err != nil {
+		return nil, err
+	}
+	if err := common.CheckVersion(v.Version, ifMatch); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,86 @@
+package video
+
+import (
+	"context"
+	"smart-scene-app-api/internal/models"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"sort"
+)
+
+const facetCharacterLimit = 100
+
+// getFacets counts the videos matching the filters per tag, status and
+// character. A multi-select category with a selected tag is counted with the
+// filter on its own tags removed.
+func (s *videoService) getFacets(ctx context.Context, f *videoListFilters) (*videoModel.VideoFacets, error) {
+	db := s.sc.DB()
+	clauses := f.clauses(db, 0)
+
+	ownCategories := f.multiSelectCategories()
+	tagCounts, err := s.videoRepo.CountByTag(ctx, nil, ownCategories, clauses...)
+	if err != nil {
+		return nil, err
+	}
+	for _, categoryID := range ownCategories {
+		counts, err := s.videoRepo.CountByTag(ctx, []int{categoryID}, nil, f.clauses(db, categoryID)...)
+		if err != nil {
+			return nil, err
+		}
+		tagCounts = append(tagCounts, counts...)
+	}
+
+	statusCounts, err := s.videoRepo.CountWithGroup(ctx, models.QueryParams{}, "status", clauses...)
+	if err != nil {
+		return nil, err
+	}
+
+	characters, err := s.videoRepo.CountByCharacter(ctx, facetCharacterLimit, clauses...)
+	if err != nil {
+		return nil, err
+	}
+	if characters == nil {
+		characters = []videoModel.CharacterFacet{}
+	}
+
+	statuses := make([]videoModel.StatusFacet, 0, len(videoModel.VideoStatuses))
+	for _, status := range videoModel.VideoStatuses {
+		statuses = append(statuses, videoModel.StatusFacet{Status: status, Count: statusCounts[status]})
+	}
+
+	return &videoModel.VideoFacets{
+		Tags:       groupTagFacets(tagCounts),
+		Statuses:   statuses,
+		Characters: characters,
+	}, nil
+}
+
+// groupTagFacets groups tag counts by category, ordered by category priority.
+// Tags keep the order of the counts within their category.
+func groupTagFacets(counts []videoModel.TagFacetCount) []videoModel.TagCategoryFacet {
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].CategoryPriority != counts[j].CategoryPriority {
+			return counts[i].CategoryPriority < counts[j].CategoryPriority
+		}
+		return counts[i].CategoryID < counts[j].CategoryID
+	})
+
+	categories := []videoModel.TagCategoryFacet{}
+	for _, c := range counts {
+		if len(categories) == 0 || categories[len(categories)-1].CategoryID != c.CategoryID {
+			categories = append(categories, videoModel.TagCategoryFacet{
+				CategoryID:   c.CategoryID,
+				CategoryName: c.CategoryName,
+				CategoryCode: c.CategoryCode,
+				FilterType:   c.FilterType,
+			})
+		}
+		category := &categories[len(categories)-1]
+		category.Tags = append(category.Tags, videoModel.TagFacet{
+			TagID:   c.TagID,
+			TagName: c.TagName,
+			TagCode: c.TagCode,
+			Count:   c.Count,
+		})
+	}
+	return categories
+}
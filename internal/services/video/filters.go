@@ -0,0 +1,232 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smart-scene-app-api/common"
+	tagModels "smart-scene-app-api/internal/models/tag"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories"
+	"smart-scene-app-api/internal/repositories/video"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// videoListFilters holds the filters of a video listing. Selected tags are
+// grouped by category: a video must have one tag of every group. Keeping the
+// groups apart lets facets leave out the filter of their own category.
+type videoListFilters struct {
+	common []repositories.Clause
+	search string
+
+	tagGroups      []tagFilterGroup
+	excludedTagIDs []int
+}
+
+// tagFilterGroup is the set of tags selected in a category. An empty group
+// matches no video.
+type tagFilterGroup struct {
+	categoryID int
+	filterType string
+	tagIDs     []int
+}
+
+// ListFilterClauses returns the clauses selecting the videos that match the
+// filters of a video listing, for services aggregating over those videos.
+// Visibility is left to the caller.
+func ListFilterClauses(ctx context.Context, db *gorm.DB, queryParams videoModel.VideoFilterAndPagination) ([]repositories.Clause, error) {
+	f, err := buildListFilters(ctx, video.NewRepository(db), queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return f.clauses(db, 0), nil
+}
+
+func buildListFilters(ctx context.Context, videoRepo video.Repository, queryParams videoModel.VideoFilterAndPagination) (*videoListFilters, error) {
+	f := &videoListFilters{search: strings.TrimSpace(queryParams.Q)}
+
+	if queryParams.Title != "" {
+		f.common = append(f.common, func(tx *gorm.DB) {
+			tx.Where("title ILIKE ?", "%"+queryParams.Title+"%")
+		})
+	}
+	if f.search != "" {
+		f.common = append(f.common, video.SearchMatch(f.search))
+	}
+	if queryParams.Status != "" {
+		f.common = append(f.common, func(tx *gorm.DB) {
+			tx.Where("status = ?", queryParams.Status)
+		})
+	}
+	if queryParams.CreatedBy != uuid.Nil {
+		f.common = append(f.common, func(tx *gorm.DB) {
+			tx.Where("created_by = ?", queryParams.CreatedBy)
+		})
+	}
+
+	if len(queryParams.TagIDs) > 0 || len(queryParams.TagCodes) > 0 {
+		tags, err := videoRepo.GetFilterTags(ctx, queryParams.TagIDs, splitCodes(queryParams.TagCodes))
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) == 0 {
+			// None of the requested tags exist or are active.
+			f.tagGroups = append(f.tagGroups, tagFilterGroup{})
+		}
+		for _, t := range tags {
+			f.addTag(t)
+		}
+	}
+
+	for _, raw := range queryParams.TagRanges {
+		if err := addTagRange(ctx, videoRepo, f, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(queryParams.ExcludeTagIDs) > 0 || len(queryParams.ExcludeTagCodes) > 0 {
+		tags, err := videoRepo.GetFilterTags(ctx, queryParams.ExcludeTagIDs, splitCodes(queryParams.ExcludeTagCodes))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tags {
+			f.excludedTagIDs = append(f.excludedTagIDs, t.TagID)
+		}
+	}
+
+	return f, nil
+}
+
+// addTagRange selects the tags of a range category overlapping the range of a
+// "category_code:min:max" filter.
+func addTagRange(ctx context.Context, videoRepo video.Repository, f *videoListFilters, raw string) error {
+	code, min, max, err := parseTagRange(raw)
+	if err != nil {
+		return err
+	}
+
+	category, err := videoRepo.GetFilterCategory(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: unknown category %q", common.ErrInvalidTagRange, code)
+		}
+		return err
+	}
+	if category.FilterType != tagModels.FilterTypeRange {
+		return fmt.Errorf("%w: category %q is not a range category", common.ErrInvalidTagRange, code)
+	}
+
+	tags, err := videoRepo.GetRangeFilterTags(ctx, category.ID, min, max)
+	if err != nil {
+		return err
+	}
+	f.group(category.ID, category.FilterType)
+	for _, t := range tags {
+		f.addTag(t)
+	}
+	return nil
+}
+
+func parseTagRange(raw string) (string, *float64, *float64, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) != 3 || parts[0] == "" || (parts[1] == "" && parts[2] == "") {
+		return "", nil, nil, fmt.Errorf("%w: %q, expected category_code:min:max", common.ErrInvalidTagRange, raw)
+	}
+
+	bounds := make([]*float64, 2)
+	for i, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("%w: %q is not a number", common.ErrInvalidTagRange, part)
+		}
+		bounds[i] = &value
+	}
+	if bounds[0] != nil && bounds[1] != nil && *bounds[0] > *bounds[1] {
+		return "", nil, nil, fmt.Errorf("%w: min is greater than max in %q", common.ErrInvalidTagRange, raw)
+	}
+	return parts[0], bounds[0], bounds[1], nil
+}
+
+func splitCodes(values []string) []string {
+	var codes []string
+	for _, value := range values {
+		codes = append(codes, strings.Split(value, ",")...)
+	}
+	return codes
+}
+
+// group returns the group of a category, creating it if needed.
+func (f *videoListFilters) group(categoryID int, filterType string) *tagFilterGroup {
+	for i := range f.tagGroups {
+		if f.tagGroups[i].categoryID == categoryID && categoryID != 0 {
+			return &f.tagGroups[i]
+		}
+	}
+	f.tagGroups = append(f.tagGroups, tagFilterGroup{categoryID: categoryID, filterType: filterType})
+	return &f.tagGroups[len(f.tagGroups)-1]
+}
+
+func (f *videoListFilters) addTag(t videoModel.FilterTag) {
+	g := f.group(t.CategoryID, t.FilterType)
+	for _, id := range g.tagIDs {
+		if id == t.TagID {
+			return
+		}
+	}
+	g.tagIDs = append(g.tagIDs, t.TagID)
+}
+
+// clauses returns the repository clauses of the filters. The tag group of
+// exceptCategoryID is left out; 0 keeps every group.
+func (f *videoListFilters) clauses(db *gorm.DB, exceptCategoryID int) []repositories.Clause {
+	clauses := append([]repositories.Clause{}, f.common...)
+
+	for _, g := range f.tagGroups {
+		if exceptCategoryID != 0 && g.categoryID == exceptCategoryID {
+			continue
+		}
+		if len(g.tagIDs) == 0 {
+			return append(clauses, func(tx *gorm.DB) {
+				tx.Where("1 = 0")
+			})
+		}
+
+		tagIDs := g.tagIDs
+		clauses = append(clauses, func(tx *gorm.DB) {
+			subQuery := db.Table("video_tags vt").
+				Select("vt.video_id").
+				Where("vt.tag_id IN ?", tagIDs)
+			tx.Where("videos.id IN (?)", subQuery)
+		})
+	}
+
+	if len(f.excludedTagIDs) > 0 {
+		clauses = append(clauses, func(tx *gorm.DB) {
+			subQuery := db.Table("video_tags vt").
+				Select("vt.video_id").
+				Where("vt.tag_id IN ?", f.excludedTagIDs)
+			tx.Where("videos.id NOT IN (?)", subQuery)
+		})
+	}
+
+	return clauses
+}
+
+// multiSelectCategories returns the categories with a selected tag whose
+// facets show alternatives: multi-select and range categories.
+func (f *videoListFilters) multiSelectCategories() []int {
+	var categoryIDs []int
+	for _, g := range f.tagGroups {
+		if g.categoryID != 0 && (g.filterType == tagModels.FilterTypeMultiple || g.filterType == tagModels.FilterTypeRange) {
+			categoryIDs = append(categoryIDs, g.categoryID)
+		}
+	}
+	return categoryIDs
+}
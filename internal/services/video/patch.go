@@ -0,0 +1,131 @@
+package video
+
+import (
+	"encoding/json"
+	"fmt"
+	"smart-scene-app-api/common"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// videoPatchFields maps the fields a merge patch may set to a decoder
+// returning the column value. A null member resets the field to its zero
+// value, except for the title which is required.
+var videoPatchFields = map[string]func(current *videoModel.Video, raw json.RawMessage) (string, interface{}, error){
+	"title": func(_ *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		var title *string
+		if err := json.Unmarshal(raw, &title); err != nil || title == nil || *title == "" {
+			return "", nil, fmt.Errorf("%w: title must be a non-empty string", common.ErrInvalidPatch)
+		}
+		return "title", *title, nil
+	},
+	"thumbnail_url": func(_ *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		var url *string
+		if err := json.Unmarshal(raw, &url); err != nil {
+			return "", nil, fmt.Errorf("%w: thumbnail_url must be a string or null", common.ErrInvalidPatch)
+		}
+		if url == nil {
+			return "thumbnail_url", "", nil
+		}
+		return "thumbnail_url", *url, nil
+	},
+	"duration": func(_ *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		n, err := decodePatchCount(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: duration must be a non-negative integer or null", common.ErrInvalidPatch)
+		}
+		return "duration", n, nil
+	},
+	"character_count": func(_ *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		n, err := decodePatchCount(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: character_count must be a non-negative integer or null", common.ErrInvalidPatch)
+		}
+		return "character_count", n, nil
+	},
+	"has_character_analysis": func(_ *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		var flag *bool
+		if err := json.Unmarshal(raw, &flag); err != nil {
+			return "", nil, fmt.Errorf("%w: has_character_analysis must be a boolean or null", common.ErrInvalidPatch)
+		}
+		return "has_character_analysis", flag != nil && *flag, nil
+	},
+	"metadata": func(current *videoModel.Video, raw json.RawMessage) (string, interface{}, error) {
+		var patch interface{}
+		if err := json.Unmarshal(raw, &patch); err != nil {
+			return "", nil, fmt.Errorf("%w: metadata: %s", common.ErrInvalidPatch, err.Error())
+		}
+		if patch == nil {
+			return "metadata", nil, nil
+		}
+		if _, ok := patch.(map[string]interface{}); !ok {
+			return "", nil, fmt.Errorf("%w: metadata must be an object or null", common.ErrInvalidPatch)
+		}
+		merged := common.MergePatch(map[string]interface{}(current.Metadata), patch).(map[string]interface{})
+		return "metadata", common.JSON(merged), nil
+	},
+}
+
+func decodePatchCount(raw json.RawMessage) (int, error) {
+	var n *int
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return 0, err
+	}
+	if n == nil {
+		return 0, nil
+	}
+	if *n < 0 {
+		return 0, fmt.Errorf("negative value %d", *n)
+	}
+	return *n, nil
+}
+
+// PatchVideo applies an RFC 7396 merge patch to a video the viewer can edit.
+// Only the fields in videoPatchFields can be patched; metadata is merged
+// deeply, so a null member deletes that key. Unlike UpdateVideo, zero values
+// such as false or 0 are written. A non-zero ifMatch makes the patch
+// conditional on the current version of the video.
+func (s *videoService) PatchVideo(id string, patch []byte, ifMatch int, viewer common.Viewer) (*videoModel.Video, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return nil, fmt.Errorf("%w: the patch must be a JSON object", common.ErrInvalidPatch)
+	}
+	for name := range members {
+		if _, ok := videoPatchFields[name]; !ok {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", common.ErrInvalidPatch, name)
+		}
+	}
+
+	var patched *videoModel.Video
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		current, err := LockVideo(ctx, tx, uuidID, viewer, videoModel.AccessEditor, ifMatch)
+		if err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"updated_by": viewer.UserID}
+		for name, raw := range members {
+			column, value, err := videoPatchFields[name](current, raw)
+			if err != nil {
+				return err
+			}
+			columns[column] = value
+		}
+
+		patched, err = video.NewRepository(tx).UpdateColumns(ctx, uuidID, columns)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
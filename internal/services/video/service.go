@@ -1,108 +1,145 @@
 package video
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"smart-scene-app-api/common"
+	"smart-scene-app-api/config"
 	"smart-scene-app-api/internal/models"
 	videoModel "smart-scene-app-api/internal/models/video"
 	"smart-scene-app-api/internal/repositories"
 	"smart-scene-app-api/internal/repositories/video"
+	jobService "smart-scene-app-api/internal/services/job"
+	"smart-scene-app-api/pkg/storage"
 	"smart-scene-app-api/server"
 
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Service interface {
-	GetAllVideos(queryParams videoModel.VideoFilterAndPagination) (*videoModel.VideoListResponse, error)
-	GetVideoDetail(id string) (*videoModel.Video, error)
+	GetAllVideos(queryParams videoModel.VideoFilterAndPagination, viewer common.Viewer) (*videoModel.VideoListResponse, error)
+	GetVideoDetail(id string, viewer common.Viewer) (*videoModel.Video, error)
 	CreateVideo(video videoModel.Video) (*videoModel.Video, error)
-	UpdateVideo(id string, video videoModel.Video) (*videoModel.Video, error)
-	DeleteVideo(id string) error
+	CreateVideoWith(video videoModel.Video, inTx func(tx *gorm.DB, created *videoModel.Video) error) (*videoModel.Video, error)
+	UpdateVideo(id string, video videoModel.Video, ifMatch int, viewer common.Viewer) (*videoModel.Video, error)
+	PatchVideo(id string, patch []byte, ifMatch int, viewer common.Viewer) (*videoModel.Video, error)
+	DeleteVideo(id string, ifMatch int, viewer common.Viewer) error
+	GetTrashVideos(queryParams videoModel.VideoTrashFilterAndPagination, viewer common.Viewer) (*videoModel.VideoTrashListResponse, error)
+	RestoreVideo(id string, viewer common.Viewer) (*videoModel.Video, error)
+	PurgeTrashedVideos() (int, error)
+	TransitionVideoStatus(id string, req videoModel.VideoStatusTransitionRequest, viewer common.Viewer) (*videoModel.Video, error)
+	GetVideoStatusHistory(id string, viewer common.Viewer) ([]videoModel.VideoStatusHistory, error)
+	GetVideoSharing(id string, viewer common.Viewer) (*videoModel.VideoSharingResponse, error)
+	SetVideoVisibility(id string, req videoModel.VideoVisibilityRequest, viewer common.Viewer) (*videoModel.VideoSharingResponse, error)
+	ShareVideo(id string, req videoModel.VideoShareRequest, viewer common.Viewer) (*videoModel.VideoSharingResponse, error)
+	UnshareVideo(id string, shareID string, viewer common.Viewer) (*videoModel.VideoSharingResponse, error)
 }
 
 type videoService struct {
-	sc        server.ServerContext
-	videoRepo video.Repository
+	sc                server.ServerContext
+	videoRepo         video.Repository
+	statusHistoryRepo video.StatusHistoryRepository
+	shareRepo         video.ShareRepository
+	storage           storage.ObjectStorage
+	jobService        jobService.Service
 }
 
+const (
+	defaultSignedURLTTL       = time.Hour
+	defaultTrashRetentionDays = 30
+	purgeBatchSize            = 100
+)
+
 func NewVideoService(sc server.ServerContext) Service {
 	return &videoService{
-		sc:        sc,
-		videoRepo: video.NewRepository(sc.DB()),
+		sc:                sc,
+		videoRepo:         video.NewRepository(sc.DB()),
+		statusHistoryRepo: video.NewStatusHistoryRepository(sc.DB()),
+		shareRepo:         video.NewShareRepository(sc.DB()),
+		storage:           sc.Storage(),
+		jobService:        jobService.NewJobService(sc),
 	}
 }
 
-func (s *videoService) GetAllVideos(queryParams videoModel.VideoFilterAndPagination) (*videoModel.VideoListResponse, error) {
+// GetAllVideos lists the videos visible to viewer.
+func (s *videoService) GetAllVideos(queryParams videoModel.VideoFilterAndPagination, viewer common.Viewer) (*videoModel.VideoListResponse, error) {
 	queryParams.VerifyPaging()
+	// Checked up front so that an invalid sort is reported even when no video
+	// matches.
+	if _, err := (models.QuerySort{Origin: queryParams.Sort}).Parse(videoModel.Video{}.SortColumns()); err != nil {
+		return nil, err
+	}
 
 	limit := queryParams.PageSize
 	offset := (queryParams.Page - 1) * queryParams.PageSize
 
-	var filters []repositories.Clause
-
-	if queryParams.Title != "" {
-		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("title ILIKE ?", "%"+queryParams.Title+"%")
-		})
+	listFilters, err := buildListFilters(s.sc.Ctx(), s.videoRepo, queryParams)
+	if err != nil {
+		return nil, err
 	}
-	if queryParams.Status != "" {
-		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("status = ?", queryParams.Status)
-		})
+	listFilters.common = append(listFilters.common, video.VisibleTo(viewer))
+	search := listFilters.search
+	filters := listFilters.clauses(s.sc.DB(), 0)
+
+	response := &videoModel.VideoListResponse{
+		BaseListResponse: models.BaseListResponse{
+			PageSize: queryParams.PageSize,
+			Items:    []videoModel.VideoListingResponse{},
+		},
 	}
-	if queryParams.CreatedBy != uuid.Nil {
-		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("created_by = ?", queryParams.CreatedBy)
-		})
+
+	if queryParams.Facets {
+		facets, err := s.getFacets(s.sc.Ctx(), listFilters)
+		if err != nil {
+			return nil, err
+		}
+		response.Extra = facets
 	}
 
-	if len(queryParams.TagIDs) > 0 || len(queryParams.TagCodes) > 0 {
-		filters = append(filters, func(tx *gorm.DB) {
-			subQuery := s.sc.DB().
-				Table("video_tags vt").
-				Select("vt.video_id").
-				Joins("JOIN tags t ON vt.tag_id = t.id").
-				Where("t.is_active = ?", true)
-
-			if len(queryParams.TagIDs) > 0 {
-				subQuery = subQuery.Where("vt.tag_id IN ?", queryParams.TagIDs)
-			}
-			if len(queryParams.TagCodes) > 0 {
-				var codes []string
-				for _, code := range queryParams.TagCodes {
-					splitCodes := strings.Split(code, ",")
-					codes = append(codes, splitCodes...)
-				}
-				subQuery = subQuery.Where("t.code IN ?", codes)
-			}
+	sort := queryParams.Sort
+	if queryParams.UseCursor() {
+		// Keyset pagination needs a column order, so searches are not
+		// ordered by relevance in cursor mode.
+		if sort == "" {
+			sort = "created_at.desc"
+		}
+		page, err := s.videoRepo.ListByCursor(s.sc.Ctx(), models.QueryParams{
+			Limit:     limit,
+			QuerySort: models.QuerySort{Origin: sort},
+		}, queryParams.Cursor, filters...)
+		if err != nil {
+			return nil, err
+		}
 
-			tx.Where("id IN (?)", subQuery)
-		})
+		response.Items, err = s.toListingItems(page.Items, search)
+		if err != nil {
+			return nil, err
+		}
+		response.NextCursor = page.NextCursor
+		response.PrevCursor = page.PrevCursor
+		return response, nil
 	}
 
 	total, err := s.videoRepo.Count(s.sc.Ctx(), models.QueryParams{}, filters...)
 	if err != nil {
 		return nil, err
 	}
-
-	response := &videoModel.VideoListResponse{
-		BaseListResponse: models.BaseListResponse{
-			Total:    int(total),
-			Page:     queryParams.Page,
-			PageSize: queryParams.PageSize,
-			Items:    []videoModel.VideoListingResponse{},
-		},
-	}
+	response.Total = int(total)
+	response.Page = queryParams.Page
 
 	if total == 0 {
 		return response, nil
 	}
 
-	sort := queryParams.Sort
-	if sort == "" {
+	pageFilters := filters
+	if sort == "" && search != "" {
+		// Searches are ordered by relevance unless a sort is asked for.
+		pageFilters = append(pageFilters[:len(pageFilters):len(pageFilters)], video.SearchRankOrder(search))
+	} else if sort == "" {
 		sort = "created_at.desc"
 	}
 
@@ -114,11 +151,19 @@ func (s *videoService) GetAllVideos(queryParams videoModel.VideoFilterAndPaginat
 		},
 	}
 
-	videos, err := s.videoRepo.List(s.sc.Ctx(), repoQueryParams, filters...)
+	videos, err := s.videoRepo.List(s.sc.Ctx(), repoQueryParams, pageFilters...)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Items, err = s.toListingItems(videos, search)
 	if err != nil {
 		return nil, err
 	}
+	return response, nil
+}
 
+func (s *videoService) toListingItems(videos []*videoModel.Video, search string) ([]videoModel.VideoListingResponse, error) {
 	videoIDs := make([]uuid.UUID, 0, len(videos))
 	for _, v := range videos {
 		if v != nil {
@@ -131,6 +176,14 @@ func (s *videoService) GetAllVideos(queryParams videoModel.VideoFilterAndPaginat
 		return nil, err
 	}
 
+	var searchHits map[uuid.UUID]videoModel.VideoSearchHit
+	if search != "" {
+		searchHits, err = s.videoRepo.GetSearchHits(s.sc.Ctx(), videoIDs, search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	items := make([]videoModel.VideoListingResponse, 0, len(videos))
 	for _, v := range videos {
 		if v != nil {
@@ -139,83 +192,412 @@ func (s *videoService) GetAllVideos(queryParams videoModel.VideoFilterAndPaginat
 				tags = []videoModel.VideoTagInfo{}
 			}
 
+			playbackURL, thumbnailURL, err := s.resolveURLs(v.FilePath, v.ThumbnailURL)
+			if err != nil {
+				return nil, err
+			}
+
 			item := videoModel.VideoListingResponse{
-				ID:               v.ID,
-				Title:            v.Title,
-				ThumbnailURL:     v.ThumbnailURL,
-				Duration:         v.Duration,
-				CharacterCount:   v.CharacterCount,
-				Status:           v.Status,
-				FilePath:         v.FilePath,
-				CreatedAt:        v.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				UpdatedAt:        v.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				Tags:             tags,
-				VisibleTagsCount: len(tags),
-				TotalTagsCount:   len(tags),
-				Metadata:         v.Metadata,
+				ID:                 v.ID,
+				Title:              v.Title,
+				ThumbnailURL:       v.ThumbnailURL,
+				Duration:           v.Duration,
+				CharacterCount:     v.CharacterCount,
+				Status:             v.Status,
+				FilePath:           v.FilePath,
+				PlaybackURL:        playbackURL,
+				ThumbnailSignedURL: thumbnailURL,
+				CreatedAt:          v.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				UpdatedAt:          v.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				Tags:               tags,
+				VisibleTagsCount:   len(tags),
+				TotalTagsCount:     len(tags),
+				Metadata:           v.Metadata,
+			}
+			if hit, ok := searchHits[v.ID]; ok {
+				item.Search = &hit
 			}
 			items = append(items, item)
 		}
 	}
-
-	slog.Info("messages", "messages", items[0].ID)
-	response.Items = items
-	return response, nil
+	return items, nil
 }
 
-func (s *videoService) GetVideoDetail(id string) (*videoModel.Video, error) {
+func (s *videoService) GetVideoDetail(id string, viewer common.Viewer) (*videoModel.Video, error) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, common.ErrInvalidUUID
 	}
-	video, err := s.videoRepo.GetByID(s.sc.Ctx(), uuidID)
+	video, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer)
 	if err != nil {
 		return nil, err
 	}
-	if video == nil {
-		return nil, common.ErrVideoNotFound
+
+	video.PlaybackURL, video.ThumbnailSignedURL, err = s.resolveURLs(video.FilePath, video.ThumbnailURL)
+	if err != nil {
+		return nil, err
 	}
 
 	return video, nil
 }
 
+// resolveURLs returns client-usable URLs for a video file and its thumbnail.
+func (s *videoService) resolveURLs(filePath, thumbnailURL string) (string, string, error) {
+	ttl := time.Duration(config.Config.Storage.SignedURLTTL) * time.Second
+	if ttl <= 0 {
+		ttl = defaultSignedURLTTL
+	}
+
+	playbackURL, err := storage.ResolveURL(s.sc.Ctx(), s.storage, filePath, ttl)
+	if err != nil {
+		return "", "", err
+	}
+	thumbnailSignedURL, err := storage.ResolveURL(s.sc.Ctx(), s.storage, thumbnailURL, ttl)
+	if err != nil {
+		return "", "", err
+	}
+	return playbackURL, thumbnailSignedURL, nil
+}
+
 func (s *videoService) CreateVideo(video videoModel.Video) (*videoModel.Video, error) {
-	video.ID = uuid.New()
-	videoRes, err := s.videoRepo.Create(s.sc.Ctx(), &video)
+	return s.CreateVideoWith(video, nil)
+}
+
+// CreateVideoWith creates a video and runs inTx, when set, in the same
+// transaction, so that the video exists only if inTx succeeds.
+func (s *videoService) CreateVideoWith(newVideo videoModel.Video, inTx func(tx *gorm.DB, created *videoModel.Video) error) (*videoModel.Video, error) {
+	newVideo.ID = uuid.New()
+	newVideo.Status = videoModel.VideoStatusPending
+	if newVideo.Visibility == "" {
+		newVideo.Visibility = videoModel.VisibilityPrivate
+	}
+	if !videoModel.IsValidVisibility(newVideo.Visibility) {
+		return nil, common.ErrInvalidVisibility
+	}
+
+	var videoRes *videoModel.Video
+	err := s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		videoRes, err = video.NewRepository(tx).Create(s.sc.Ctx(), &newVideo)
+		if err != nil {
+			return err
+		}
+		if videoRes == nil {
+			return common.ErrVideoNotFound
+		}
+		if inTx != nil {
+			return inTx(tx, videoRes)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if videoRes == nil {
-		return nil, common.ErrVideoNotFound
+	// The video is already stored; if enqueueing fails the worker's pending
+	// sweep picks it up later.
+	if err := s.jobService.EnqueueVideoAnalysis(videoRes.ID); err != nil {
+		slog.Error("failed to enqueue video analysis", "video_id", videoRes.ID, "error", err)
 	}
 	return videoRes, nil
 }
 
-func (s *videoService) UpdateVideo(id string, video videoModel.Video) (*videoModel.Video, error) {
+// UpdateVideo updates a video the viewer can edit. Ownership and visibility
+// are not changed here; see SetVideoVisibility. A non-zero ifMatch makes the
+// update conditional on the current version of the video.
+func (s *videoService) UpdateVideo(id string, changes videoModel.Video, ifMatch int, viewer common.Viewer) (*videoModel.Video, error) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, common.ErrInvalidUUID
 	}
-	video.ID = uuidID
-	updatedVideo, err := s.videoRepo.Update(s.sc.Ctx(), uuidID, &video)
+
+	var updatedVideo *videoModel.Video
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		current, err := LockVideo(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessEditor, ifMatch)
+		if err != nil {
+			return err
+		}
+		if changes.Status != "" && current.Status != changes.Status {
+			return common.ErrVideoStatusNotUpdatable
+		}
+
+		changes.ID = uuidID
+		changes.CreatedBy = uuid.Nil
+		changes.UpdatedBy = viewer.UserID
+		changes.Visibility = ""
+		changes.Version = current.Version
+		updatedVideo, err = video.NewRepository(tx).Update(s.sc.Ctx(), uuidID, &changes)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return updatedVideo, nil
 }
 
-func (s *videoService) DeleteVideo(id string) error {
+// DeleteVideo moves a video to the trash; it is purged after the retention
+// period unless restored. A non-zero ifMatch makes the deletion conditional
+// on the current version of the video.
+func (s *videoService) DeleteVideo(id string, ifMatch int, viewer common.Viewer) error {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		return common.ErrInvalidUUID
 	}
 
-	err = s.videoRepo.Delete(s.sc.Ctx(), func(tx *gorm.DB) {
-		tx.Where("id = ?", uuidID)
+	return s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := LockVideo(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessOwner, ifMatch); err != nil {
+			return err
+		}
+		err := video.NewRepository(tx).SoftDelete(s.sc.Ctx(), uuidID, viewer.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return common.ErrVideoNotFound
+		}
+		return err
+	})
+}
+
+// GetTrashVideos lists trashed videos. Only admins see the videos of other
+// users, since only owners can restore them.
+func (s *videoService) GetTrashVideos(queryParams videoModel.VideoTrashFilterAndPagination, viewer common.Viewer) (*videoModel.VideoTrashListResponse, error) {
+	queryParams.VerifyPaging()
+
+	var filters []repositories.Clause
+	if !viewer.Admin {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("created_by = ?", viewer.UserID)
+		})
+	}
+	if queryParams.Title != "" {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("title ILIKE ?", "%"+queryParams.Title+"%")
+		})
+	}
+	if queryParams.DeletedBy != uuid.Nil {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("deleted_by = ?", queryParams.DeletedBy)
+		})
+	}
+
+	videos, total, err := s.videoRepo.ListTrashed(s.sc.Ctx(), models.QueryParams{
+		Offset: (queryParams.Page - 1) * queryParams.PageSize,
+		Limit:  queryParams.PageSize,
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	retention := trashRetention()
+	items := make([]videoModel.VideoTrashItem, 0, len(videos))
+	for _, v := range videos {
+		items = append(items, videoModel.VideoTrashItem{
+			ID:           v.ID,
+			Title:        v.Title,
+			ThumbnailURL: v.ThumbnailURL,
+			Duration:     v.Duration,
+			Status:       v.Status,
+			CreatedBy:    v.CreatedBy,
+			CreatedAt:    v.CreatedAt,
+			DeletedAt:    v.DeletedAt.Time,
+			DeletedBy:    v.DeletedBy,
+			PurgeAt:      v.DeletedAt.Time.Add(retention),
+		})
+	}
+
+	return &videoModel.VideoTrashListResponse{
+		BaseListResponse: models.BaseListResponse{
+			Total:    int(total),
+			Page:     queryParams.Page,
+			PageSize: queryParams.PageSize,
+		},
+		Items: items,
+	}, nil
+}
+
+func (s *videoService) RestoreVideo(id string, viewer common.Viewer) (*videoModel.Video, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	// The trashed row is locked so that a concurrent purge either completes
+	// first or finds the video restored.
+	var restored *videoModel.Video
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		repo := video.NewRepository(tx)
+		trashed, err := repo.GetTrashedByIDForUpdate(s.sc.Ctx(), uuidID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common.ErrVideoNotInTrash
+			}
+			return err
+		}
+		if !viewer.Admin && trashed.CreatedBy != viewer.UserID {
+			return common.ErrVideoNotInTrash
+		}
+
+		if err := repo.Restore(s.sc.Ctx(), uuidID); err != nil {
+			return err
+		}
+		restored, err = repo.UpdateColumns(s.sc.Ctx(), uuidID, map[string]interface{}{"updated_by": viewer.UserID})
+		return err
 	})
 	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// PurgeTrashedVideos hard-deletes videos that have been in the trash longer
+// than the retention period. Each video is purged with its dependent rows in a
+// single transaction; stored files are removed afterwards on a best-effort
+// basis.
+func (s *videoService) PurgeTrashedVideos() (int, error) {
+	ctx := s.sc.Ctx()
+	ids, err := s.videoRepo.GetTrashedIDsBefore(ctx, time.Now().Add(-trashRetention()), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		var removed *videoModel.Video
+		err := s.sc.DB().Transaction(func(tx *gorm.DB) error {
+			v, err := video.NewRepository(tx).Purge(ctx, id)
+			removed = v
+			return err
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Restored or purged concurrently.
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+
+		for _, path := range []string{removed.FilePath, removed.ThumbnailURL} {
+			if s.storage == nil || path == "" || storage.IsExternalURL(path) {
+				continue
+			}
+			if err := s.storage.DeleteObject(ctx, path); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+				slog.Error("failed to delete purged video file", "video_id", id, "path", path, "error", err)
+			}
+		}
+	}
+	return purged, nil
+}
+
+func trashRetention() time.Duration {
+	days := config.Config.Trash.RetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (s *videoService) TransitionVideoStatus(id string, req videoModel.VideoStatusTransitionRequest, viewer common.Viewer) (*videoModel.Video, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if _, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessEditor); err != nil {
+		return nil, err
+	}
+
+	var updated *videoModel.Video
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		updated, err = ApplyStatusTransition(s.sc.Ctx(), tx, uuidID, req.Status, req.Reason, &viewer.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *videoService) GetVideoStatusHistory(id string, viewer common.Viewer) ([]videoModel.VideoStatusHistory, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+
+	if _, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessViewer); err != nil {
+		return nil, err
+	}
+
+	return s.statusHistoryRepo.ListByVideoID(s.sc.Ctx(), uuidID)
+}
+
+// ApplyStatusTransition moves a video to toStatus inside tx and records the
+// change in video_status_histories. The video row is locked for the rest of
+// the transaction so concurrent transitions are serialized. A nil actorID
+// marks the transition as performed by the system (pipeline, worker, ...).
+func ApplyStatusTransition(ctx context.Context, tx *gorm.DB, videoID uuid.UUID, toStatus, reason string, actorID *uuid.UUID) (*videoModel.Video, error) {
+	if !videoModel.IsValidVideoStatus(toStatus) {
+		return nil, common.ErrInvalidVideoStatus
+	}
+
+	videoRepo := video.NewRepository(tx)
+	current, err := videoRepo.GetByIDForUpdate(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrVideoNotFound
+		}
+		return nil, err
+	}
+
+	if !videoModel.CanTransitionVideoStatus(current.Status, toStatus) {
+		return nil, common.ErrInvalidStatusTransition
+	}
+
+	columns := map[string]interface{}{"status": toStatus}
+	if actorID != nil {
+		columns["updated_by"] = *actorID
+	}
+	updated, err := videoRepo.UpdateColumns(ctx, videoID, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	actorType := videoModel.StatusActorUser
+	if actorID == nil {
+		actorType = videoModel.StatusActorSystem
+	}
+	_, err = video.NewStatusHistoryRepository(tx).Create(ctx, &videoModel.VideoStatusHistory{
+		VideoID:    videoID,
+		FromStatus: current.Status,
+		ToStatus:   toStatus,
+		Reason:     reason,
+		ActorType:  actorType,
+		ActorID:    actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// AdvanceStatusTo walks the video through every intermediate status needed to
+// reach toStatus, recording each step. It is a no-op when the video already
+// has toStatus.
+func AdvanceStatusTo(ctx context.Context, tx *gorm.DB, videoID uuid.UUID, toStatus, reason string, actorID *uuid.UUID) error {
+	current, err := video.NewRepository(tx).GetByIDForUpdate(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return common.ErrVideoNotFound
+		}
 		return err
 	}
+
+	path := videoModel.VideoStatusPath(current.Status, toStatus)
+	if path == nil {
+		return common.ErrInvalidStatusTransition
+	}
+	for _, status := range path {
+		if _, err := ApplyStatusTransition(ctx, tx, videoID, status, reason, actorID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
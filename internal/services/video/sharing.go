@@ -0,0 +1,133 @@
+package video
+
+import (
+	"errors"
+	"smart-scene-app-api/common"
+	videoModel "smart-scene-app-api/internal/models/video"
+	"smart-scene-app-api/internal/repositories/video"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetVideoSharing returns the visibility and shares of a video. Only the owner
+// can see who a video is shared with.
+func (s *videoService) GetVideoSharing(id string, viewer common.Viewer) (*videoModel.VideoSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	v, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner)
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(v)
+}
+
+func (s *videoService) SetVideoVisibility(id string, req videoModel.VideoVisibilityRequest, viewer common.Viewer) (*videoModel.VideoSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if !videoModel.IsValidVisibility(req.Visibility) {
+		return nil, common.ErrInvalidVisibility
+	}
+	if _, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner); err != nil {
+		return nil, err
+	}
+
+	v, err := s.videoRepo.UpdateColumns(s.sc.Ctx(), uuidID, map[string]interface{}{
+		"visibility": req.Visibility,
+		"updated_by": viewer.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(v)
+}
+
+// ShareVideo grants a user or a role access to a video. Sharing again with the
+// same user or role changes the permission of the existing share. Shares only
+// take effect while the video is shared or, for editing, organization-wide.
+func (s *videoService) ShareVideo(id string, req videoModel.VideoShareRequest, viewer common.Viewer) (*videoModel.VideoSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	if (req.UserID == nil) == (req.RoleID == nil) {
+		return nil, common.ErrInvalidVideoShare
+	}
+
+	var v *videoModel.Video
+	err = s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		if v, err = AuthorizeVideo(s.sc.Ctx(), tx, uuidID, viewer, videoModel.AccessOwner); err != nil {
+			return err
+		}
+
+		shareRepo := video.NewShareRepository(tx)
+		existing, err := shareRepo.FindByGrantee(s.sc.Ctx(), uuidID, req.UserID, req.RoleID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			_, err = shareRepo.UpdateColumns(s.sc.Ctx(), existing.ID, map[string]interface{}{"permission": req.Permission})
+			return err
+		}
+		_, err = shareRepo.Create(s.sc.Ctx(), &videoModel.VideoShare{
+			VideoID:    uuidID,
+			UserID:     req.UserID,
+			RoleID:     req.RoleID,
+			Permission: req.Permission,
+			CreatedBy:  viewer.UserID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sharingOf(v)
+}
+
+func (s *videoService) UnshareVideo(id string, shareID string, viewer common.Viewer) (*videoModel.VideoSharingResponse, error) {
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	shareUUID, err := uuid.Parse(shareID)
+	if err != nil {
+		return nil, common.ErrInvalidUUID
+	}
+	v, err := AuthorizeVideo(s.sc.Ctx(), s.sc.DB(), uuidID, viewer, videoModel.AccessOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	ofVideo := func(tx *gorm.DB) {
+		tx.Where("id = ? AND video_id = ?", shareUUID, uuidID)
+	}
+	if _, err := s.shareRepo.GetDetailByConditions(s.sc.Ctx(), ofVideo); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrVideoShareNotFound
+		}
+		return nil, err
+	}
+	if err := s.shareRepo.Delete(s.sc.Ctx(), ofVideo); err != nil {
+		return nil, err
+	}
+	return s.sharingOf(v)
+}
+
+func (s *videoService) sharingOf(v *videoModel.Video) (*videoModel.VideoSharingResponse, error) {
+	shares, err := s.shareRepo.ListByVideoID(s.sc.Ctx(), v.ID)
+	if err != nil {
+		return nil, err
+	}
+	if shares == nil {
+		shares = []videoModel.VideoShare{}
+	}
+	return &videoModel.VideoSharingResponse{
+		VideoID:    v.ID,
+		Visibility: v.Visibility,
+		Shares:     shares,
+	}, nil
+}
@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/config"
+	characterModel "smart-scene-app-api/internal/models/character"
+	videoModel "smart-scene-app-api/internal/models/video"
+	webhookModel "smart-scene-app-api/internal/models/webhook"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	characterService "smart-scene-app-api/internal/services/character"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/pkg/redis"
+	"smart-scene-app-api/server"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTimestampTolerance = 300
+
+	// eventProcessingTTL bounds how long an event stays locked when the
+	// process handling it dies before releasing it.
+	eventProcessingTTL = 10 * 60
+	eventDoneTTL       = 7 * 24 * 60 * 60
+
+	eventStateProcessing = "processing"
+	eventStateDone       = "done"
+)
+
+type Service interface {
+	VerifyDelivery(integration string, delivery webhookModel.Delivery, body []byte) error
+	HandleEvent(integration string, event webhookModel.Event) (*webhookModel.DeliveryResult, error)
+	ReleaseDelivery(integration string, delivery webhookModel.Delivery) error
+}
+
+type webhookService struct {
+	sc               server.ServerContext
+	redis            redis.ClientI
+	characterService characterService.Service
+}
+
+func NewWebhookService(sc server.ServerContext) Service {
+	return &webhookService{
+		sc:               sc,
+		redis:            sc.Redis(),
+		characterService: characterService.NewCharacterService(sc),
+	}
+}
+
+// VerifyDelivery authenticates a delivery and consumes its nonce. A delivery
+// is rejected when the signature does not match, the timestamp is outside the
+// tolerance window or the nonce has been seen within that window, unless the
+// event it carries was already applied.
+func (s *webhookService) VerifyDelivery(integration string, delivery webhookModel.Delivery, body []byte) error {
+	secret, ok := config.WebhookSecret(integration)
+	if !ok {
+		return common.ErrWebhookUnknownIntegration
+	}
+
+	timestamp, err := strconv.ParseInt(delivery.Timestamp, 10, 64)
+	if err != nil || delivery.Nonce == "" {
+		return common.ErrWebhookInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(delivery.Timestamp + "." + delivery.Nonce + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	signature := strings.TrimPrefix(delivery.Signature, "sha256=")
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return common.ErrWebhookInvalidSignature
+	}
+
+	tolerance := config.Config.Webhook.TimestampTolerance
+	if tolerance <= 0 {
+		tolerance = defaultTimestampTolerance
+	}
+	age := time.Now().Unix() - timestamp
+	if age > tolerance || age < -tolerance {
+		return common.ErrWebhookTimestampExpired
+	}
+
+	if s.redis == nil {
+		return fmt.Errorf("redis is not available")
+	}
+	// Nonces only need to outlive the timestamp window: older deliveries are
+	// already rejected by the timestamp check.
+	fresh, err := s.redis.SetNX(s.sc.Ctx(), nonceKey(integration, delivery.Nonce), delivery.Timestamp, 2*tolerance)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		// A byte-identical retry of an event that was already applied, whose
+		// response the sender lost, is let through so that HandleEvent
+		// acknowledges it as a duplicate.
+		var event struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(body, &event) == nil && event.ID != "" {
+			state, err := s.redis.Get(s.sc.Ctx(), eventKey(integration, event.ID))
+			if err != nil && !errors.Is(err, redis.ErrRecordNotFound) {
+				return err
+			}
+			if state == eventStateDone {
+				return nil
+			}
+		}
+		return common.ErrWebhookReplay
+	}
+	return nil
+}
+
+// ReleaseDelivery forgets the nonce of a delivery that could not be handled,
+// so that the sender can retry it unchanged.
+func (s *webhookService) ReleaseDelivery(integration string, delivery webhookModel.Delivery) error {
+	if s.redis == nil || delivery.Nonce == "" {
+		return nil
+	}
+	return s.redis.Delete(s.sc.Ctx(), nonceKey(integration, delivery.Nonce))
+}
+
+// HandleEvent applies an event at most once per integration and event ID.
+// Redeliveries of an event that was already applied, events of unknown type,
+// and events about unknown videos or videos already completed or failed are
+// acknowledged without side effects.
+func (s *webhookService) HandleEvent(integration string, event webhookModel.Event) (*webhookModel.DeliveryResult, error) {
+	ctx := s.sc.Ctx()
+	key := eventKey(integration, event.ID)
+
+	acquired, err := s.redis.SetNX(ctx, key, eventStateProcessing, eventProcessingTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		state, err := s.redis.Get(ctx, key)
+		if err != nil && !errors.Is(err, redis.ErrRecordNotFound) {
+			return nil, err
+		}
+		if state == eventStateProcessing {
+			return nil, common.ErrWebhookEventInProgress
+		}
+		return &webhookModel.DeliveryResult{EventID: event.ID, Status: webhookModel.DeliveryDuplicate}, nil
+	}
+
+	status, err := s.applyEvent(integration, event)
+	if err != nil {
+		// Release the event so the sender can retry it.
+		_ = s.redis.Delete(ctx, key)
+		return nil, err
+	}
+
+	if err := s.redis.Set(ctx, key, eventStateDone, eventDoneTTL); err != nil {
+		return nil, err
+	}
+	return &webhookModel.DeliveryResult{EventID: event.ID, Status: status}, nil
+}
+
+func (s *webhookService) applyEvent(integration string, event webhookModel.Event) (string, error) {
+	switch event.Type {
+	case webhookModel.EventTypeProgress:
+		var data webhookModel.ProgressEventData
+		if err := decodeEventData(event, &data); err != nil {
+			return "", err
+		}
+		return s.applyProgress(integration, event, data)
+
+	case webhookModel.EventTypeCompleted:
+		var data characterModel.IngestAppearancesRequest
+		if err := decodeEventData(event, &data); err != nil {
+			return "", err
+		}
+		if _, err := s.characterService.ReplaceVideoAppearances(event.VideoID.String(), data, 0, nil); err != nil {
+			if errors.Is(err, common.ErrVideoNotFound) {
+				// The video was deleted or trashed since the analysis started.
+				return webhookModel.DeliveryIgnored, nil
+			}
+			return "", err
+		}
+		return webhookModel.DeliveryProcessed, nil
+
+	case webhookModel.EventTypeFailed:
+		var data webhookModel.FailedEventData
+		if err := decodeEventData(event, &data); err != nil {
+			return "", err
+		}
+		reason := fmt.Sprintf("analysis failed (%s)", integration)
+		if data.Reason != "" {
+			reason = fmt.Sprintf("analysis failed (%s): %s", integration, data.Reason)
+		}
+		status := webhookModel.DeliveryProcessed
+		err := s.sc.DB().Transaction(func(tx *gorm.DB) error {
+			_, ok, err := lockEventVideo(s.sc.Ctx(), tx, event.VideoID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				status = webhookModel.DeliveryIgnored
+				return nil
+			}
+			return videoService.AdvanceStatusTo(s.sc.Ctx(), tx, event.VideoID, videoModel.VideoStatusFailed, reason, nil)
+		})
+		if err != nil {
+			return "", err
+		}
+		return status, nil
+	}
+
+	return webhookModel.DeliveryIgnored, nil
+}
+
+// lockEventVideo locks the video an event is about. It reports false when the
+// event comes too late to apply: the video is unknown, trashed, or already
+// completed or failed.
+func lockEventVideo(ctx context.Context, tx *gorm.DB, videoID uuid.UUID) (*videoModel.Video, bool, error) {
+	current, err := videoRepo.NewRepository(tx).GetByIDForUpdate(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if current.Status == videoModel.VideoStatusCompleted || current.Status == videoModel.VideoStatusFailed {
+		return nil, false, nil
+	}
+	return current, true, nil
+}
+
+// applyProgress moves the video into processing and records the reported
+// progress in its metadata. Progress reported for an unknown video or one
+// that already completed or failed is stale and ignored.
+func (s *webhookService) applyProgress(integration string, event webhookModel.Event, data webhookModel.ProgressEventData) (string, error) {
+	status := webhookModel.DeliveryProcessed
+	err := s.sc.DB().Transaction(func(tx *gorm.DB) error {
+		ctx := s.sc.Ctx()
+		repo := videoRepo.NewRepository(tx)
+
+		current, ok, err := lockEventVideo(ctx, tx, event.VideoID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			status = webhookModel.DeliveryIgnored
+			return nil
+		}
+
+		reason := fmt.Sprintf("analysis started (%s)", integration)
+		if err := videoService.AdvanceStatusTo(ctx, tx, event.VideoID, videoModel.VideoStatusProcessing, reason, nil); err != nil {
+			return err
+		}
+
+		metadata := current.Metadata
+		if metadata == nil {
+			metadata = common.JSON{}
+		}
+		metadata["analysis_progress"] = data.Progress
+		if data.Message != "" {
+			metadata["analysis_message"] = data.Message
+		}
+		_, err = repo.UpdateColumns(ctx, event.VideoID, map[string]interface{}{"metadata": metadata})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func decodeEventData(event webhookModel.Event, out interface{}) error {
+	if len(event.Data) > 0 {
+		if err := json.Unmarshal(event.Data, out); err != nil {
+			return fmt.Errorf("%w: %s", common.ErrInvalidWebhookEvent, err.Error())
+		}
+	}
+	if err := binding.Validator.ValidateStruct(out); err != nil {
+		return fmt.Errorf("%w: %s", common.ErrInvalidWebhookEvent, err.Error())
+	}
+	return nil
+}
+
+func nonceKey(integration, nonce string) string {
+	return fmt.Sprintf("webhook:nonce:%s:%s", integration, nonce)
+}
+
+func eventKey(integration, eventID string) string {
+	return fmt.Sprintf("webhook:event:%s:%s", integration, eventID)
+}
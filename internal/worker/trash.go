@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"smart-scene-app-api/config"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/server"
+	"time"
+)
+
+const defaultPurgeInterval = time.Hour
+
+// RegisterTrashPurge schedules the purge of videos whose trash retention has
+// expired.
+func RegisterTrashPurge(w *Worker, sc server.ServerContext) {
+	interval := time.Duration(config.Config.Trash.PurgeInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	videos := videoService.NewVideoService(sc)
+	w.Schedule("purge_trashed_videos", interval, func(ctx context.Context) error {
+		purged, err := videos.PurgeTrashedVideos()
+		if purged > 0 {
+			sc.GetLogger().Info().Println(fmt.Sprintf("purged %v trashed videos", purged))
+		}
+		return err
+	})
+}
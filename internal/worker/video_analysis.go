@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/config"
+	"smart-scene-app-api/internal/models"
+	jobModel "smart-scene-app-api/internal/models/job"
+	videoModel "smart-scene-app-api/internal/models/video"
+	videoRepo "smart-scene-app-api/internal/repositories/video"
+	jobService "smart-scene-app-api/internal/services/job"
+	videoService "smart-scene-app-api/internal/services/video"
+	"smart-scene-app-api/pkg/redis"
+	"smart-scene-app-api/pkg/storage"
+	"smart-scene-app-api/server"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pendingSweepInterval = time.Minute
+	// pendingSweepGrace leaves time for the enqueue done at creation.
+	pendingSweepGrace = 2 * time.Minute
+	pendingSweepBatch = 500
+	detectorTimeout   = 30 * time.Second
+)
+
+// NewVideoAnalysisWorker builds the worker consuming the video analysis queue,
+// configured from the worker settings.
+func NewVideoAnalysisWorker(prefix string, sc server.ServerContext) (error, *Worker) {
+	if sc.Redis() == nil {
+		return fmt.Errorf("redis is not available"), nil
+	}
+
+	cfg := config.Config.Worker
+	w := NewWorker(prefix, sc, jobService.VideoAnalysisQueue(sc.Redis()), Options{
+		Concurrency:  cfg.Concurrency,
+		PollInterval: time.Duration(cfg.PollInterval) * time.Second,
+		LockExpiry:   time.Duration(cfg.VisibilityTimeout) * time.Second,
+	})
+
+	analysis := &videoAnalysis{sc: sc, jobs: jobService.NewJobService(sc), client: &http.Client{Timeout: detectorTimeout}}
+	w.Handle(common.JOB_TYPE_VIDEO_ANALYSIS, analysis.process, analysis.deadLetter)
+	w.Schedule("enqueue_pending_videos", pendingSweepInterval, analysis.enqueuePending)
+	return nil, w
+}
+
+type videoAnalysis struct {
+	sc     server.ServerContext
+	jobs   jobService.Service
+	client *http.Client
+}
+
+// process moves the video to processing and hands it to the detector, which
+// reports back through the webhook endpoint.
+func (a *videoAnalysis) process(ctx context.Context, job *redis.QueueJob) error {
+	var payload jobModel.VideoAnalysisPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return err
+	}
+
+	// Without a detector nothing would ever complete the video, so the job
+	// fails before the video is moved to processing.
+	detectorURL := config.Config.Worker.DetectorURL
+	if detectorURL == "" {
+		return errors.New("no detector URL is configured")
+	}
+
+	var video *videoModel.Video
+	err := a.sc.DB().Transaction(func(tx *gorm.DB) error {
+		current, err := videoRepo.NewRepository(tx).GetByIDForUpdate(ctx, payload.VideoID)
+		if err != nil {
+			return err
+		}
+		video = current
+		if current.Status != videoModel.VideoStatusPending && current.Status != videoModel.VideoStatusProcessing {
+			return nil
+		}
+		return videoService.AdvanceStatusTo(ctx, tx, payload.VideoID, videoModel.VideoStatusProcessing, "analysis job started", nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The video is gone; nothing left to analyze.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if video.Status == videoModel.VideoStatusCompleted || video.Status == videoModel.VideoStatusFailed {
+		return nil
+	}
+
+	ttl := time.Duration(config.Config.Storage.SignedURLTTL) * time.Second
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	videoURL, err := storage.ResolveURL(ctx, a.sc.Storage(), video.FilePath, ttl)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(jobModel.DetectorRequest{
+		JobID:    job.ID,
+		VideoID:  video.ID,
+		VideoURL: videoURL,
+		Duration: video.Duration,
+		Attempt:  job.Attempts,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, detectorURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("detector responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// deadLetter marks the video failed once its analysis job is given up.
+func (a *videoAnalysis) deadLetter(ctx context.Context, job *redis.QueueJob, cause error) error {
+	var payload jobModel.VideoAnalysisPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("analysis job failed after %d attempts: %v", job.Attempts, cause)
+	err := a.sc.DB().Transaction(func(tx *gorm.DB) error {
+		return videoService.AdvanceStatusTo(ctx, tx, payload.VideoID, videoModel.VideoStatusFailed, reason, nil)
+	})
+	if errors.Is(err, common.ErrVideoNotFound) || errors.Is(err, common.ErrInvalidStatusTransition) {
+		return nil
+	}
+	return err
+}
+
+// enqueuePending re-enqueues pending videos whose job was never queued, e.g.
+// because redis was unavailable when they were created. Enqueueing is
+// idempotent, so videos that are already queued are left alone.
+func (a *videoAnalysis) enqueuePending(ctx context.Context) error {
+	videos, err := videoRepo.NewRepository(a.sc.DB()).List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("status = ? AND created_at < ?", videoModel.VideoStatusPending, time.Now().Add(-pendingSweepGrace)).
+			Order("created_at").Limit(pendingSweepBatch)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, video := range videos {
+		if err := a.jobs.EnqueueVideoAnalysis(video.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"smart-scene-app-api/common"
+	"smart-scene-app-api/pkg/redis"
+	"smart-scene-app-api/server"
+	"sync"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+)
+
+// HandlerFunc processes a leased job. Returning an error nacks the job.
+type HandlerFunc func(ctx context.Context, job *redis.QueueJob) error
+
+// DeadLetterFunc is called once a job has used up its attempts.
+type DeadLetterFunc func(ctx context.Context, job *redis.QueueJob, cause error) error
+
+type jobHandler struct {
+	process    HandlerFunc
+	deadLetter DeadLetterFunc
+}
+
+type scheduledTask struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+type Options struct {
+	Concurrency  int
+	PollInterval time.Duration
+	// LockExpiry bounds how long a job lock is held when its worker dies.
+	LockExpiry time.Duration
+	// Heartbeat is how often the lease and lock of a running job are
+	// extended; it must be shorter than both the visibility timeout and
+	// LockExpiry.
+	Heartbeat time.Duration
+}
+
+// Worker consumes a redis queue. It is registered as a server service: Run
+// starts the consumers and scheduled tasks, Stop waits for in-flight jobs.
+type Worker struct {
+	prefix   string
+	sc       server.ServerContext
+	queue    *redis.Queue
+	options  Options
+	handlers map[string]jobHandler
+	tasks    []scheduledTask
+
+	once   sync.Once
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(prefix string, sc server.ServerContext, queue *redis.Queue, options Options) *Worker {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = 2 * time.Second
+	}
+	if options.LockExpiry <= 0 {
+		options.LockExpiry = 5 * time.Minute
+	}
+	if options.Heartbeat <= 0 {
+		options.Heartbeat = options.LockExpiry / 3
+	}
+	return &Worker{
+		prefix:   prefix,
+		sc:       sc,
+		queue:    queue,
+		options:  options,
+		handlers: map[string]jobHandler{},
+	}
+}
+
+// Handle registers the handler of a job type. deadLetter may be nil.
+func (w *Worker) Handle(jobType string, process HandlerFunc, deadLetter DeadLetterFunc) {
+	w.handlers[jobType] = jobHandler{process: process, deadLetter: deadLetter}
+}
+
+// Schedule registers a periodic task. Every worker instance ticks, but the task
+// runs at most once per interval across instances.
+func (w *Worker) Schedule(name string, interval time.Duration, run func(ctx context.Context) error) {
+	w.tasks = append(w.tasks, scheduledTask{name: name, interval: interval, run: run})
+}
+
+func (w *Worker) Run() error {
+	w.once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+
+		for i := 0; i < w.options.Concurrency; i++ {
+			w.wg.Add(1)
+			go w.consume(ctx)
+		}
+		for _, task := range w.tasks {
+			w.wg.Add(1)
+			go w.runTask(ctx, task)
+		}
+	})
+	return nil
+}
+
+func (w *Worker) Stop() <-chan bool {
+	stop := make(chan bool)
+	go func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+		w.wg.Wait()
+		stop <- true
+	}()
+	return stop
+}
+
+func (w *Worker) GetPrefix() string {
+	return w.prefix
+}
+
+func (w *Worker) Get() interface{} {
+	return w
+}
+
+func (w *Worker) redsync() redsync.Redsync {
+	return w.sc.GetRedisRedsync(common.PREFIX_MAIN_REDIS)
+}
+
+func (w *Worker) consume(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, dead, err := w.queue.Lease(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.sc.GetLogger().Error().Println(fmt.Sprintf("%v lease failed: %v", w.prefix, err))
+		}
+		for _, d := range dead {
+			w.sc.GetLogger().Error().Println(fmt.Sprintf("%v job %v dead-lettered: %v", w.prefix, d.ID, d.LastError))
+			w.deadLetter(context.Background(), d, errors.New(d.LastError))
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.options.PollInterval):
+			}
+			continue
+		}
+
+		// Jobs must not be cut short by shutdown: they are acked or nacked
+		// with a context of their own.
+		w.process(context.Background(), job)
+	}
+}
+
+// process runs a job under a redsync lock on its ID so that a job whose
+// lease expired while its first worker is still busy is not run twice.
+func (w *Worker) process(ctx context.Context, job *redis.QueueJob) {
+	rs := w.redsync()
+	mutex := rs.NewMutex(fmt.Sprintf("lock:job:%s", job.ID),
+		redsync.WithExpiry(w.options.LockExpiry), redsync.WithTries(1))
+	if err := mutex.LockContext(ctx); err != nil {
+		// Another worker holds the job; its lease will be handed out
+		// again if that worker never finishes.
+		return
+	}
+	defer mutex.UnlockContext(ctx)
+
+	handler, ok := w.handlers[job.Type]
+	var cause error
+	if !ok {
+		cause = fmt.Errorf("no handler for job type %q", job.Type)
+	} else {
+		beatCtx, stopBeat := context.WithCancel(ctx)
+		go w.heartbeat(beatCtx, job, mutex)
+		cause = w.safeRun(ctx, handler.process, job)
+		stopBeat()
+	}
+
+	if cause == nil {
+		if err := w.queue.Ack(ctx, job); err != nil {
+			w.sc.GetLogger().Error().Println(fmt.Sprintf("%v ack %v failed: %v", w.prefix, job.ID, err))
+		}
+		return
+	}
+
+	w.sc.GetLogger().Error().Println(fmt.Sprintf("%v job %v attempt %v failed: %v", w.prefix, job.ID, job.Attempts, cause))
+	dead, err := w.queue.Nack(ctx, job, cause)
+	if err != nil {
+		w.sc.GetLogger().Error().Println(fmt.Sprintf("%v nack %v failed: %v", w.prefix, job.ID, err))
+		return
+	}
+	if dead {
+		w.deadLetter(ctx, job, cause)
+	}
+}
+
+// deadLetter runs the dead-letter hook of a job that has used up its attempts,
+// whether nacked or dead-lettered when its last lease expired.
+func (w *Worker) deadLetter(ctx context.Context, job *redis.QueueJob, cause error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok || handler.deadLetter == nil {
+		return
+	}
+	if err := handler.deadLetter(ctx, job, cause); err != nil {
+		w.sc.GetLogger().Error().Println(fmt.Sprintf("%v dead-letter hook for %v failed: %v", w.prefix, job.ID, err))
+	}
+}
+
+func (w *Worker) heartbeat(ctx context.Context, job *redis.QueueJob, mutex *redsync.Mutex) {
+	ticker := time.NewTicker(w.options.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.queue.Extend(ctx, job); err != nil && ctx.Err() == nil {
+				w.sc.GetLogger().Warning().Println(fmt.Sprintf("%v extend lease of %v failed: %v", w.prefix, job.ID, err))
+			}
+			if _, err := mutex.ExtendContext(ctx); err != nil && ctx.Err() == nil {
+				w.sc.GetLogger().Warning().Println(fmt.Sprintf("%v extend lock of %v failed: %v", w.prefix, job.ID, err))
+			}
+		}
+	}
+}
+
+func (w *Worker) safeRun(ctx context.Context, fn HandlerFunc, job *redis.QueueJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(ctx, job)
+}
+
+func (w *Worker) runTask(ctx context.Context, task scheduledTask) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(task.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// The lock is left to expire rather than released so that other
+		// instances skip the task until the next interval.
+		rs := w.redsync()
+		mutex := rs.NewMutex(fmt.Sprintf("lock:task:%s", task.name),
+			redsync.WithExpiry(task.interval), redsync.WithTries(1))
+		if err := mutex.LockContext(ctx); err != nil {
+			var taken redsync.ErrTaken
+			if !errors.Is(err, redsync.ErrFailed) && !errors.As(err, &taken) && ctx.Err() == nil {
+				w.sc.GetLogger().Error().Println(fmt.Sprintf("%v task %v lock failed: %v", w.prefix, task.name, err))
+			}
+			continue
+		}
+
+		if err := task.run(ctx); err != nil {
+			w.sc.GetLogger().Error().Println(fmt.Sprintf("%v task %v failed: %v", w.prefix, task.name, err))
+		}
+	}
+}
@@ -6,4 +6,6 @@ type StorageConfigureParams struct {
 	Token     string
 	Endpoint  string
 	Region    string
+	Bucket    string
+	Acl       string
 }
@@ -0,0 +1,91 @@
+package awss3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"smart-scene-app-api/pkg/storage"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+var _ storage.ObjectStorage = (*S3Storage)(nil)
+
+func (s *S3Storage) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(s.params.Bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if s.params.Acl != "" {
+		input.ACL = aws.String(s.params.Acl)
+	}
+
+	// The upload manager streams large bodies as a multipart upload.
+	_, err := s3manager.NewUploaderWithClient(s.s3).UploadWithContext(ctx, input)
+	return err
+}
+
+func (s *S3Storage) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.params.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3Storage) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.s3.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.params.Bucket),
+		Key:    aws.String(key),
+	})
+	return translateError(err)
+}
+
+func (s *S3Storage) StatObject(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	out, err := s.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.params.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &storage.ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(out.ContentLength),
+		ContentType:  aws.StringValue(out.ContentType),
+		LastModified: aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+func (s *S3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, _ := s.s3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.params.Bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(ttl)
+}
+
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return storage.ErrObjectNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,271 @@
+// Package interval implements sets of half-open intervals [Start, End) over
+// frames or seconds.
+//
+// A Set is always normalized: its intervals are non-empty, sorted by start,
+// and neither overlap nor touch, so two sets covering the same points are
+// equal element by element. Every operation returns a normalized set and
+// leaves its operands unchanged.
+package interval
+
+import "sort"
+
+// Number is the type of the bounds: frame numbers or seconds.
+type Number interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Interval is the half-open range [Start, End). It is empty when End <= Start.
+type Interval[T Number] struct {
+	Start T `json:"start"`
+	End   T `json:"end"`
+}
+
+func (i Interval[T]) Empty() bool {
+	return i.End <= i.Start
+}
+
+func (i Interval[T]) Len() T {
+	if i.Empty() {
+		return 0
+	}
+	return i.End - i.Start
+}
+
+// Contains reports whether p lies in [Start, End).
+func (i Interval[T]) Contains(p T) bool {
+	return i.Start <= p && p < i.End
+}
+
+// Set is a normalized set of intervals; the zero value is the empty set. Build
+// sets with New rather than by converting a slice, which could break the
+// normalization.
+type Set[T Number] []Interval[T]
+
+// New returns the union of intervals as a normalized set.
+func New[T Number](intervals ...Interval[T]) Set[T] {
+	sorted := make([]Interval[T], 0, len(intervals))
+	for _, i := range intervals {
+		if !i.Empty() {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Start < sorted[b].Start
+	})
+
+	var s Set[T]
+	for _, i := range sorted {
+		s = s.push(i)
+	}
+	return s
+}
+
+// push appends i, which must not start before the last interval of s, merging
+// it into that interval when they overlap or touch.
+func (s Set[T]) push(i Interval[T]) Set[T] {
+	if i.Empty() {
+		return s
+	}
+	if n := len(s); n > 0 && i.Start <= s[n-1].End {
+		if i.End > s[n-1].End {
+			s[n-1].End = i.End
+		}
+		return s
+	}
+	return append(s, i)
+}
+
+func (s Set[T]) Empty() bool {
+	return len(s) == 0
+}
+
+// Len is the total length covered by the set.
+func (s Set[T]) Len() T {
+	var total T
+	for _, i := range s {
+		total += i.Len()
+	}
+	return total
+}
+
+// Contains reports whether p lies in one of the intervals of the set.
+func (s Set[T]) Contains(p T) bool {
+	k := sort.Search(len(s), func(k int) bool { return s[k].End > p })
+	return k < len(s) && s[k].Contains(p)
+}
+
+func (s Set[T]) Equal(o Set[T]) bool {
+	if len(s) != len(o) {
+		return false
+	}
+	for k := range s {
+		if s[k] != o[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the smallest interval containing the set, empty when the set
+// is.
+func (s Set[T]) Bounds() Interval[T] {
+	if len(s) == 0 {
+		return Interval[T]{}
+	}
+	return Interval[T]{Start: s[0].Start, End: s[len(s)-1].End}
+}
+
+// Union returns the points in s or o.
+func (s Set[T]) Union(o Set[T]) Set[T] {
+	out := make(Set[T], 0, len(s)+len(o))
+	a, b := 0, 0
+	for a < len(s) || b < len(o) {
+		if b == len(o) || (a < len(s) && s[a].Start <= o[b].Start) {
+			out = out.push(s[a])
+			a++
+		} else {
+			out = out.push(o[b])
+			b++
+		}
+	}
+	return out
+}
+
+// Intersect returns the points in both s and o.
+func (s Set[T]) Intersect(o Set[T]) Set[T] {
+	var out Set[T]
+	a, b := 0, 0
+	for a < len(s) && b < len(o) {
+		start, end := max(s[a].Start, o[b].Start), min(s[a].End, o[b].End)
+		out = out.push(Interval[T]{Start: start, End: end})
+		if s[a].End < o[b].End {
+			a++
+		} else {
+			b++
+		}
+	}
+	return out
+}
+
+// Difference returns the points in s but not in o.
+func (s Set[T]) Difference(o Set[T]) Set[T] {
+	var out Set[T]
+	b := 0
+	for _, i := range s {
+		start := i.Start
+		for b < len(o) && o[b].End <= start {
+			b++
+		}
+		for k := b; k < len(o) && o[k].Start < i.End; k++ {
+			out = out.push(Interval[T]{Start: start, End: o[k].Start})
+			start = max(start, o[k].End)
+		}
+		out = out.push(Interval[T]{Start: start, End: i.End})
+	}
+	return out
+}
+
+// Complement returns the points of within that are not in s, for instance the
+// parts of a video where a character does not appear.
+func (s Set[T]) Complement(within Interval[T]) Set[T] {
+	return New(within).Difference(s)
+}
+
+// Clip returns the points of s inside within.
+func (s Set[T]) Clip(within Interval[T]) Set[T] {
+	return s.Intersect(New(within))
+}
+
+// BridgeGaps fills the gaps between consecutive intervals that are at most
+// maxGap long, joining the intervals around them.
+func (s Set[T]) BridgeGaps(maxGap T) Set[T] {
+	var out Set[T]
+	for _, i := range s {
+		if n := len(out); n > 0 && i.Start-out[n-1].End <= maxGap {
+			out[n-1].End = i.End
+			continue
+		}
+		out = append(out, i)
+	}
+	return out
+}
+
+// MinLength drops the intervals shorter than length.
+func (s Set[T]) MinLength(length T) Set[T] {
+	var out Set[T]
+	for _, i := range s {
+		if i.Len() >= length {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// MaxLength drops the intervals longer than length.
+func (s Set[T]) MaxLength(length T) Set[T] {
+	var out Set[T]
+	for _, i := range s {
+		if i.Len() <= length {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// UnionAll returns the union of sets, the empty set when there are none.
+func UnionAll[T Number](sets ...Set[T]) Set[T] {
+	var out Set[T]
+	for _, s := range sets {
+		out = out.Union(s)
+	}
+	return out
+}
+
+// IntersectAll returns the intersection of sets, the empty set when there are
+// none.
+func IntersectAll[T Number](sets ...Set[T]) Set[T] {
+	if len(sets) == 0 {
+		return nil
+	}
+	out := sets[0]
+	for _, s := range sets[1:] {
+		out = out.Intersect(s)
+	}
+	return out
+}
+
+// AtLeast returns the points covered by at least n of sets: AtLeast(1, ...) is
+// their union and AtLeast(len(sets), ...) their intersection. It returns the
+// empty set when n is not between 1 and len(sets).
+func AtLeast[T Number](n int, sets ...Set[T]) Set[T] {
+	if n <= 0 || n > len(sets) {
+		return nil
+	}
+
+	type event struct {
+		at    T
+		delta int
+	}
+	var events []event
+	for _, s := range sets {
+		for _, i := range s {
+			events = append(events, event{i.Start, 1}, event{i.End, -1})
+		}
+	}
+	sort.Slice(events, func(a, b int) bool {
+		return events[a].at < events[b].at
+	})
+
+	var out Set[T]
+	count := 0
+	for k := 0; k < len(events); {
+		at := events[k].at
+		for ; k < len(events) && events[k].at == at; k++ {
+			count += events[k].delta
+		}
+		if count >= n && k < len(events) {
+			out = out.push(Interval[T]{Start: at, End: events[k].at})
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package interval
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// domain bounds the generated sets; it is small so that intervals often
+// overlap and touch.
+var domain = Interval[int]{Start: 0, End: 60}
+
+// randomSet is a set built with New from random intervals, some of them
+// empty, clipped to domain.
+type randomSet struct {
+	Set[int]
+}
+
+func (randomSet) Generate(r *rand.Rand, size int) reflect.Value {
+	intervals := make([]Interval[int], r.Intn(size%8+1))
+	for k := range intervals {
+		start := domain.Start + r.Intn(domain.Len())
+		intervals[k] = Interval[int]{Start: start, End: start + r.Intn(15) - 2}
+	}
+	return reflect.ValueOf(randomSet{New(intervals...).Clip(domain)})
+}
+
+func check(t *testing.T, property any) {
+	t.Helper()
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func normalized(s Set[int]) bool {
+	for k, i := range s {
+		if i.Empty() || (k > 0 && s[k-1].End >= i.Start) {
+			return false
+		}
+	}
+	return true
+}
+
+func subset(a, b Set[int]) bool {
+	return a.Difference(b).Empty()
+}
+
+func sets(rs []randomSet) []Set[int] {
+	out := make([]Set[int], len(rs))
+	for k, r := range rs {
+		out[k] = r.Set
+	}
+	return out
+}
+
+func TestUnionIntersectCommutative(t *testing.T) {
+	check(t, func(a, b randomSet) bool {
+		return a.Union(b.Set).Equal(b.Union(a.Set)) &&
+			a.Intersect(b.Set).Equal(b.Intersect(a.Set))
+	})
+}
+
+func TestUnionIntersectAssociative(t *testing.T) {
+	check(t, func(a, b, c randomSet) bool {
+		return a.Union(b.Set).Union(c.Set).Equal(a.Union(b.Union(c.Set))) &&
+			a.Intersect(b.Set).Intersect(c.Set).Equal(a.Intersect(b.Intersect(c.Set)))
+	})
+}
+
+func TestOperationsNormalized(t *testing.T) {
+	check(t, func(a, b randomSet) bool {
+		return normalized(a.Set) &&
+			normalized(a.Union(b.Set)) &&
+			normalized(a.Intersect(b.Set)) &&
+			normalized(a.Difference(b.Set)) &&
+			normalized(a.Complement(domain))
+	})
+}
+
+func TestDifferenceIsIntersectComplement(t *testing.T) {
+	check(t, func(a, b randomSet) bool {
+		return a.Difference(b.Set).Equal(a.Intersect(b.Complement(domain)))
+	})
+}
+
+func TestDeMorgan(t *testing.T) {
+	check(t, func(a, b randomSet) bool {
+		return a.Union(b.Set).Complement(domain).Equal(a.Complement(domain).Intersect(b.Complement(domain))) &&
+			a.Intersect(b.Set).Complement(domain).Equal(a.Complement(domain).Union(b.Complement(domain)))
+	})
+}
+
+func TestNewIdempotent(t *testing.T) {
+	check(t, func(a randomSet) bool {
+		again := New(a.Set...)
+		return normalized(again) && again.Equal(a.Set)
+	})
+}
+
+func TestBridgeGapsMonotone(t *testing.T) {
+	check(t, func(a, b randomSet, g1, g2 uint8) bool {
+		small, large := int(min(g1%20, g2%20)), int(max(g1%20, g2%20))
+		bridged := a.BridgeGaps(small)
+		return normalized(bridged) &&
+			subset(a.Set, bridged) &&
+			subset(bridged, a.BridgeGaps(large)) &&
+			subset(a.BridgeGaps(small), a.Union(b.Set).BridgeGaps(small))
+	})
+}
+
+func TestMinLengthMonotone(t *testing.T) {
+	check(t, func(a, b randomSet, l1, l2 uint8) bool {
+		short, long := int(min(l1%20, l2%20)), int(max(l1%20, l2%20))
+		kept := a.MinLength(short)
+		return normalized(kept) &&
+			subset(kept, a.Set) &&
+			subset(a.MinLength(long), kept) &&
+			subset(kept, a.Union(b.Set).MinLength(short))
+	})
+}
+
+func TestAtLeastBounds(t *testing.T) {
+	check(t, func(rs []randomSet) bool {
+		s := sets(rs)
+		return AtLeast(1, s...).Equal(UnionAll(s...)) &&
+			AtLeast(len(s), s...).Equal(IntersectAll(s...))
+	})
+}
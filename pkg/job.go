@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,7 +15,7 @@ type Job struct {
 	prefix  string
 	timer   time.Duration
 	handler func() error
-	status  int
+	status  atomic.Int32
 }
 
 func NewJob(prefix string, timer time.Duration, handler func() error) *Job {
@@ -35,18 +36,17 @@ func (j *Job) Run() error {
 }
 
 func (j *Job) execute() {
+	if !j.status.CompareAndSwap(IDLE, RUNNING) {
+		return
+	}
+
 	defer func() {
-		j.status = IDLE
+		j.status.Store(IDLE)
 		if r := recover(); r != nil {
 			fmt.Println(fmt.Sprintf("%v job error: %v", j.prefix, r))
 		}
 	}()
 
-	if j.status == RUNNING {
-		return
-	}
-
-	j.status = RUNNING
 	if err := j.handler(); err != nil {
 		fmt.Println(fmt.Sprintf("%v is processed with %v", j.prefix, err.Error()))
 	}
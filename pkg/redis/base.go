@@ -18,10 +18,12 @@ type ClientI interface {
 	Get(ctx context.Context, key string) (string, error)
 	GetByte(ctx context.Context, key string) ([]byte, error)
 	Set(ctx context.Context, key string, value string, expiry int64) error
+	SetNX(ctx context.Context, key string, value string, expiry int64) (bool, error)
 	SetByte(ctx context.Context, key string, value []byte, expiry int64) error
 	Delete(ctx context.Context, keys ...string) error
 	Publish(ctx context.Context, channel string, message string) error
 	Subscribe(ctx context.Context, channel string) *redis.PubSub
+	Queue(name string, options QueueOptions) *Queue
 }
 
 type redisClient struct {
@@ -49,6 +51,11 @@ func (c *redisClient) Set(ctx context.Context, key string, value string, expiry
 	return c.client.Set(ctx, key, value, time.Duration(expiry)*time.Second).Err()
 }
 
+// SetNX sets key only when it does not exist yet and reports whether it did.
+func (c *redisClient) SetNX(ctx context.Context, key string, value string, expiry int64) (bool, error) {
+	return c.client.SetNX(ctx, key, value, time.Duration(expiry)*time.Second).Result()
+}
+
 func (c *redisClient) GetByte(ctx context.Context, key string) ([]byte, error) {
 	return c.client.Get(ctx, key).Bytes()
 }
@@ -0,0 +1,311 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+var ErrLeaseLost = errors.New("queue lease expired or not owned")
+
+// QueueJob is a unit of work stored in a Queue. ID is chosen by the producer
+// and makes enqueueing idempotent while the job is pending or leased.
+type QueueJob struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	LastError  string          `json:"last_error,omitempty"`
+	FailedAt   *time.Time      `json:"failed_at,omitempty"`
+
+	// Set when the job is leased.
+	Attempts   int    `json:"attempts,omitempty"`
+	LeaseToken string `json:"-"`
+}
+
+type QueueOptions struct {
+	// VisibilityTimeout is how long a leased job stays invisible before it
+	// is handed out again unless acked or nacked.
+	VisibilityTimeout time.Duration
+	// MaxAttempts is the number of leases after which a failing job is moved
+	// to the dead-letter list.
+	MaxAttempts int
+	// RetryBackoff is the base delay before a nacked job becomes visible
+	// again; it doubles with every attempt.
+	RetryBackoff time.Duration
+}
+
+// Queue is a durable at-least-once work queue. Jobs live in a hash, their IDs
+// move between a ready list, a leased set scored by lease deadline, a delayed
+// set scored by retry time, and a dead-letter list.
+type Queue struct {
+	client  redis.UniversalClient
+	name    string
+	options QueueOptions
+}
+
+func (c *redisClient) Queue(name string, options QueueOptions) *Queue {
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = 5 * time.Minute
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = 10 * time.Second
+	}
+	return &Queue{client: c.client, name: name, options: options}
+}
+
+// key uses a hash tag so that all keys of a queue share a cluster slot, which
+// the scripts require.
+func (q *Queue) key(part string) string {
+	return fmt.Sprintf("queue:{%s}:%s", q.name, part)
+}
+
+func (q *Queue) keys() []string {
+	return []string{
+		q.key("jobs"),
+		q.key("ready"),
+		q.key("leased"),
+		q.key("delayed"),
+		q.key("attempts"),
+		q.key("leases"),
+		q.key("dead"),
+	}
+}
+
+// KEYS: jobs, ready. ARGV: id, job.
+var enqueueScript = redis.NewScript(`
+if redis.call('HSETNX', KEYS[1], ARGV[1], ARGV[2]) == 0 then
+	return 0
+end
+redis.call('LPUSH', KEYS[2], ARGV[1])
+return 1
+`)
+
+// KEYS: jobs, ready, leased, delayed, attempts, leases, dead.
+// ARGV: now, deadline, token, max_attempts, dead fields (JSON members
+// appended to a job that expired on its last attempt).
+// Returns the leased job or false, its attempts, and the dead-lettered jobs.
+var leaseScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local dead = {}
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[4], '-inf', now)) do
+	redis.call('ZREM', KEYS[4], id)
+	redis.call('RPUSH', KEYS[2], id)
+end
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', now)) do
+	redis.call('ZREM', KEYS[3], id)
+	redis.call('HDEL', KEYS[6], id)
+	local attempts = tonumber(redis.call('HGET', KEYS[5], id) or 0)
+	if attempts >= tonumber(ARGV[4]) then
+		local job = redis.call('HGET', KEYS[1], id)
+		redis.call('HDEL', KEYS[5], id)
+		redis.call('HDEL', KEYS[1], id)
+		if job then
+			local deadJob = string.sub(job, 1, -2) .. ',"attempts":' .. attempts .. ARGV[5] .. '}'
+			redis.call('LPUSH', KEYS[7], deadJob)
+			table.insert(dead, deadJob)
+		end
+	else
+		redis.call('RPUSH', KEYS[2], id)
+	end
+end
+while true do
+	local id = redis.call('RPOP', KEYS[2])
+	if not id then
+		return {false, 0, dead}
+	end
+	local job = redis.call('HGET', KEYS[1], id)
+	if job then
+		redis.call('ZADD', KEYS[3], ARGV[2], id)
+		redis.call('HSET', KEYS[6], id, ARGV[3])
+		local attempts = redis.call('HINCRBY', KEYS[5], id, 1)
+		return {job, attempts, dead}
+	end
+end
+`)
+
+// KEYS: jobs, leased, attempts, leases. ARGV: id, token.
+var ackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[4], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('HDEL', KEYS[1], ARGV[1])
+return 1
+`)
+
+// KEYS: jobs, leased, delayed, attempts, leases, dead.
+// ARGV: id, token, retry_at, dead ("1" to dead-letter), dead job.
+var nackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[5], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+if ARGV[4] == '1' then
+	redis.call('HDEL', KEYS[4], ARGV[1])
+	redis.call('HDEL', KEYS[1], ARGV[1])
+	redis.call('LPUSH', KEYS[6], ARGV[5])
+else
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+end
+return 1
+`)
+
+// Enqueue adds a job and reports whether it was added; a job with the same ID
+// that is still pending or leased is left untouched.
+func (q *Queue) Enqueue(ctx context.Context, jobType, id string, payload interface{}) (bool, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	job, err := json.Marshal(QueueJob{ID: id, Type: jobType, Payload: raw, EnqueuedAt: time.Now()})
+	if err != nil {
+		return false, err
+	}
+
+	keys := q.keys()
+	added, err := enqueueScript.Run(ctx, q.client, []string{keys[0], keys[1]}, id, job).Int()
+	if err != nil {
+		return false, err
+	}
+	return added == 1, nil
+}
+
+// Lease hands out the next visible job, or nil when the queue is empty. Delayed
+// retries that are due and leases past their visibility timeout are made
+// visible first; a lease that expires on the last attempt dead-letters its
+// job, so that a job crashing its workers is not redelivered forever. The jobs
+// dead-lettered this way are returned so that the caller can act on them as
+// on a job dead-lettered by Nack.
+func (q *Queue) Lease(ctx context.Context) (*QueueJob, []*QueueJob, error) {
+	now := time.Now()
+	token := uuid.NewString()
+	keys := q.keys()
+
+	// Stored jobs carry none of these fields, see Enqueue.
+	lastError, _ := json.Marshal("lease expired: visibility timeout reached on the last attempt")
+	failedAt, _ := json.Marshal(now)
+	deadFields := fmt.Sprintf(`,"last_error":%s,"failed_at":%s`, lastError, failedAt)
+
+	res, err := leaseScript.Run(ctx, q.client, keys,
+		now.UnixMilli(), now.Add(q.options.VisibilityTimeout).UnixMilli(), token,
+		q.options.MaxAttempts, deadFields).Slice()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dead []*QueueJob
+	rawDead, _ := res[2].([]interface{})
+	for _, r := range rawDead {
+		raw, _ := r.(string)
+		var job QueueJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			return nil, nil, err
+		}
+		dead = append(dead, &job)
+	}
+
+	raw, ok := res[0].(string)
+	if !ok {
+		return nil, dead, nil
+	}
+	attempts, _ := res[1].(int64)
+	var job QueueJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, dead, err
+	}
+	job.Attempts = int(attempts)
+	job.LeaseToken = token
+	return &job, dead, nil
+}
+
+// Ack removes a successfully processed job.
+func (q *Queue) Ack(ctx context.Context, job *QueueJob) error {
+	keys := q.keys()
+	ok, err := ackScript.Run(ctx, q.client, []string{keys[0], keys[2], keys[4], keys[5]}, job.ID, job.LeaseToken).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Nack releases a failed job. It is retried with exponential backoff until it
+// reaches MaxAttempts, then moved to the dead-letter list. It reports whether
+// the job was dead-lettered.
+func (q *Queue) Nack(ctx context.Context, job *QueueJob, cause error) (bool, error) {
+	dead := job.Attempts >= q.options.MaxAttempts
+	backoff := q.options.RetryBackoff << uint(min(job.Attempts-1, 16))
+	retryAt := time.Now().Add(backoff)
+
+	deadJob := *job
+	if cause != nil {
+		deadJob.LastError = cause.Error()
+	}
+	failedAt := time.Now()
+	deadJob.FailedAt = &failedAt
+	raw, err := json.Marshal(deadJob)
+	if err != nil {
+		return false, err
+	}
+
+	deadFlag := "0"
+	if dead {
+		deadFlag = "1"
+	}
+	keys := q.keys()
+	ok, err := nackScript.Run(ctx, q.client,
+		[]string{keys[0], keys[2], keys[3], keys[4], keys[5], keys[6]},
+		job.ID, job.LeaseToken, retryAt.UnixMilli(), deadFlag, raw).Int()
+	if err != nil {
+		return false, err
+	}
+	if ok == 0 {
+		return false, ErrLeaseLost
+	}
+	return dead, nil
+}
+
+// Extend pushes the visibility deadline of a leased job, for handlers that
+// run longer than the visibility timeout.
+func (q *Queue) Extend(ctx context.Context, job *QueueJob) error {
+	owner, err := q.client.HGet(ctx, q.key("leases"), job.ID).Result()
+	if err != nil || owner != job.LeaseToken {
+		return ErrLeaseLost
+	}
+	deadline := time.Now().Add(q.options.VisibilityTimeout).UnixMilli()
+	return q.client.ZAddXX(ctx, q.key("leased"), redis.Z{Score: float64(deadline), Member: job.ID}).Err()
+}
+
+// DeadLetters returns up to limit dead-lettered jobs, most recent first.
+func (q *Queue) DeadLetters(ctx context.Context, limit int64) ([]QueueJob, error) {
+	items, err := q.client.LRange(ctx, q.key("dead"), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]QueueJob, 0, len(items))
+	for _, item := range items {
+		var job QueueJob
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
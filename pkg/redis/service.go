@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	config "smart-scene-app-api/config"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/go-redsync/redsync/v4/redis/goredis/v9"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Redis is the server service wrapping the shared redis connection. Get returns
+// a ClientI; GetRedsync returns a distributed lock factory on the same pool.
+type Redis struct {
+	prefix  string
+	client  *redisClient
+	redsync *redsync.Redsync
+}
+
+func (s *Redis) Configure(prefix string) error {
+	s.prefix = prefix
+	return nil
+}
+
+// Run connects to redis. The server runs every registered service again on
+// start, so an existing connection is kept.
+func (s *Redis) Run() error {
+	if s.client != nil {
+		return nil
+	}
+
+	configRedis := config.Config.Redis
+	if configRedis == nil {
+		return fmt.Errorf("redis is not configured")
+	}
+
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:    []string{fmt.Sprintf("%v:%v", configRedis.Host, configRedis.Port)},
+		Password: configRedis.Pass,
+		DB:       configRedis.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return err
+	}
+
+	s.client = &redisClient{client: client}
+	s.redsync = redsync.New(goredis.NewPool(client))
+	return nil
+}
+
+func (s *Redis) Get() interface{} {
+	return ClientI(s.client)
+}
+
+func (s *Redis) GetRedsync() redsync.Redsync {
+	return *s.redsync
+}
+
+func (s *Redis) GetPrefix() string {
+	return s.prefix
+}
+
+func (s *Redis) Stop() <-chan bool {
+	stop := make(chan bool)
+	go func() {
+		if s.client != nil {
+			_ = s.client.client.Close()
+		}
+		stop <- true
+	}()
+	return stop
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrObjectNotFound   = errors.New("storage object not found")
+	ErrInvalidKey       = errors.New("invalid storage key")
+	ErrInvalidSignature = errors.New("invalid or expired storage signature")
+)
+
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ObjectStorage is implemented by every storage backend (local disk, S3/DigitalOcean Spaces).
+// Keys are slash separated relative paths such as "videos/<id>/source.mp4".
+type ObjectStorage interface {
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) error
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, key string) error
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// IsExternalURL reports whether path already is an absolute URL that needs no signing.
+func IsExternalURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// ResolveURL turns a stored path into a URL a client can download from.
+// Absolute URLs are returned untouched, storage keys get a time-limited signed URL.
+func ResolveURL(ctx context.Context, st ObjectStorage, path string, ttl time.Duration) (string, error) {
+	if path == "" || IsExternalURL(path) || st == nil {
+		return path, nil
+	}
+	return st.SignedURL(ctx, path, ttl)
+}
+
+// Backend is an ObjectStorage that can be registered as a server service.
+type Backend interface {
+	ObjectStorage
+	Run() error
+	Stop() <-chan bool
+	GetPrefix() string
+	Get() interface{}
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalDownloadPath is the route serving objects of the local backend.
+const LocalDownloadPath = "/api/v1/storage/files"
+
+type LocalConfigureParams struct {
+	Root          string
+	BaseURL       string
+	SigningSecret string
+}
+
+// LocalStorage keeps objects on the local filesystem. Signed URLs point to the
+// API's own download route and carry an HMAC over the key and expiry.
+type LocalStorage struct {
+	prefix string
+	params LocalConfigureParams
+}
+
+func (s *LocalStorage) Configure(prefix string, params LocalConfigureParams) error {
+	if params.Root == "" {
+		return fmt.Errorf("local storage root cannot be empty")
+	}
+	s.prefix = prefix
+	s.params = params
+	return nil
+}
+
+func (s *LocalStorage) Get() interface{} {
+	return s
+}
+
+func (s *LocalStorage) Run() error {
+	return os.MkdirAll(s.params.Root, 0o755)
+}
+
+func (s *LocalStorage) GetPrefix() string {
+	return s.prefix
+}
+
+func (s *LocalStorage) Stop() <-chan bool {
+	stop := make(chan bool)
+	go func() {
+		stop <- true
+	}()
+	return stop
+}
+
+func (s *LocalStorage) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+func (s *LocalStorage) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *LocalStorage) DeleteObject(ctx context.Context, key string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, ErrObjectNotFound
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(key)),
+		LastModified: fi.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+
+	escaped := strings.TrimPrefix((&url.URL{Path: "/" + key}).EscapedPath(), "/")
+	return fmt.Sprintf("%s%s/%s?%s", strings.TrimRight(s.params.BaseURL, "/"), LocalDownloadPath, escaped, query.Encode()), nil
+}
+
+// VerifySignature checks a signature produced by SignedURL.
+func (s *LocalStorage) VerifySignature(key, expires, signature string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(s.sign(key, exp)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalStorage) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.params.SigningSecret))
+	mac.Write([]byte(fmt.Sprintf("%s|%d", key, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to a file below the root, rejecting keys that would escape it.
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.params.Root, filepath.FromSlash(cleaned)), nil
+}
@@ -3,7 +3,9 @@ package server
 import (
 	"context"
 	"smart-scene-app-api/pkg"
+	"smart-scene-app-api/pkg/redis"
 	"smart-scene-app-api/pkg/rest_service"
+	"smart-scene-app-api/pkg/storage"
 	"smart-scene-app-api/services/logger"
 
 	"github.com/go-redsync/redsync/v4"
@@ -24,5 +26,7 @@ type ServerContext interface {
 	GetAwsSes() *pkg.AWSSesClient
 	SetAwsSes(service *pkg.AWSSesClient)
 	DB() *gorm.DB
+	Storage() storage.ObjectStorage
+	Redis() redis.ClientI
 	Ctx() context.Context
 }
@@ -8,7 +8,9 @@ import (
 	"os/signal"
 	"smart-scene-app-api/common"
 	"smart-scene-app-api/pkg"
+	"smart-scene-app-api/pkg/redis"
 	"smart-scene-app-api/pkg/rest_service"
+	"smart-scene-app-api/pkg/storage"
 	logger2 "smart-scene-app-api/services/logger"
 	"sync"
 	"syscall"
@@ -87,14 +89,14 @@ func (s *server) Run() error {
 
 	}
 	go func() {
-		for _, job := range s.jobs {
-			go func(job *pkg.Job) {
-				s.logger.Info().Println(fmt.Sprintf("CronJob %v is running"))
+		for name, job := range s.jobs {
+			go func(name string, job *pkg.Job) {
+				s.logger.Info().Println(fmt.Sprintf("CronJob %v is running", name))
 				err := job.Run()
 				if err != nil {
 					return
 				}
-			}(job)
+			}(name, job)
 		}
 	}()
 
@@ -227,6 +229,18 @@ func (s *server) DB() *gorm.DB {
 	return s.GetService(common.PREFIX_MAIN_POSTGRES).(*gorm.DB)
 }
 
+// Storage returns the object storage backend, or nil when none is registered.
+func (s *server) Storage() storage.ObjectStorage {
+	st, _ := s.services[common.PREFIX_MAIN_STORAGE].(storage.ObjectStorage)
+	return st
+}
+
+// Redis returns the shared redis client, or nil when none is registered.
+func (s *server) Redis() redis.ClientI {
+	rd, _ := s.GetService(common.PREFIX_MAIN_REDIS).(redis.ClientI)
+	return rd
+}
+
 func (s *server) Ctx() context.Context {
 	return s.GetContext()
 }
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"smart-scene-app-api/pkg/redis"
+)
+
+func NewMainRedis(prefix string) (error, *redis.Redis) {
+	rd := &redis.Redis{}
+	if err := rd.Configure(prefix); err != nil {
+		return err, nil
+	}
+	if err := rd.Run(); err != nil {
+		return err, nil
+	}
+	return nil, rd
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"smart-scene-app-api/config"
+	awss3 "smart-scene-app-api/pkg/awsS3"
+	"smart-scene-app-api/pkg/storage"
+)
+
+const defaultLocalBaseURL = "http://localhost:8080"
+
+func NewMainStorage(prefix string) (error, storage.Backend) {
+	cfg := config.Config.Storage
+
+	switch cfg.Driver {
+	case storage.DriverS3:
+		do := config.Config.DigitalOcean
+		st := &awss3.S3Storage{}
+		err := st.Configure(prefix, awss3.StorageConfigureParams{
+			AccessKey: do.StorageAccessKey,
+			SecretKey: do.StorageSecretKey,
+			Endpoint:  do.StorageEndPoint,
+			Region:    do.StorageRegion,
+			Bucket:    do.StorageBucket,
+			Acl:       do.StorageAcl,
+		})
+		if err != nil {
+			return err, nil
+		}
+		if err := st.Run(); err != nil {
+			return err, nil
+		}
+		return nil, st
+
+	case storage.DriverLocal, "":
+		baseURL := cfg.LocalBaseURL
+		if baseURL == "" {
+			baseURL = defaultLocalBaseURL
+		}
+		secret := cfg.SigningSecret
+		if secret == "" {
+			secret = config.Config.JwtSecret
+		}
+
+		st := &storage.LocalStorage{}
+		err := st.Configure(prefix, storage.LocalConfigureParams{
+			Root:          cfg.LocalRoot,
+			BaseURL:       baseURL,
+			SigningSecret: secret,
+		})
+		if err != nil {
+			return err, nil
+		}
+		if err := st.Run(); err != nil {
+			return err, nil
+		}
+		return nil, st
+	}
+
+	return fmt.Errorf("unknown storage driver %q", cfg.Driver), nil
+}